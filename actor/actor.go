@@ -0,0 +1,274 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gyozatech/sushi/functional"
+)
+
+// ErrActorStopped is returned when a message is sent to an actor which doesn't accept messages anymore
+var ErrActorStopped = errors.New("actor is stopped")
+
+// ErrMailboxFull is returned by TryTell and TryAsk when the mailbox has reached its capacity
+var ErrMailboxFull = errors.New("actor mailbox is full")
+
+// PanicError wraps the value recovered from a panic occurred while the actor was processing a message
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("actor panicked: %v", e.Value)
+}
+
+// Directive is the decision taken by a Strategy after a panic
+type Directive int
+
+const (
+	// Resume keeps processing the next messages with the current state
+	Resume Directive = iota
+	// Restart discards the current state, creating a fresh behavior through the factory
+	Restart
+	// Stop stops the actor: the messages left in the mailbox fail with ErrActorStopped
+	Stop
+)
+
+// Strategy supervises the actor deciding what to do after a panic, given the number of restarts performed so far
+type Strategy func(err *PanicError, restarts int) Directive
+
+// RestartOnPanic always restarts the actor after a panic
+func RestartOnPanic() Strategy {
+	return func(err *PanicError, restarts int) Directive {
+		return Restart
+	}
+}
+
+// RestartAtMost restarts the actor after a panic up to maxRestarts times, then stops it
+func RestartAtMost(maxRestarts int) Strategy {
+	return func(err *PanicError, restarts int) Directive {
+		if restarts < maxRestarts {
+			return Restart
+		}
+		return Stop
+	}
+}
+
+// ResumeOnPanic ignores panics, keeping the current state
+func ResumeOnPanic() Strategy {
+	return func(err *PanicError, restarts int) Directive {
+		return Resume
+	}
+}
+
+// StopOnPanic stops the actor at the first panic
+func StopOnPanic() Strategy {
+	return func(err *PanicError, restarts int) Directive {
+		return Stop
+	}
+}
+
+type envelope[M any, R any] struct {
+	msg   M
+	reply chan functional.Either[R]
+}
+
+// Actor is a goroutine owning a state and processing the messages of a bounded mailbox one at a time:
+// the state is never shared, so it doesn't need any synchronization.
+// The state lives in the closure of the behavior returned by the factory, which is invoked again on every restart.
+type Actor[M any, R any] struct {
+	factory  func() functional.Function[M, R]
+	strategy Strategy
+	mailbox  chan envelope[M, R]
+	// mu guards stopped only, and is never held while sending to the mailbox: the senders in flight
+	// are tracked instead, so that the mailbox is drained only once all of them have returned
+	mu       sync.RWMutex
+	stopped  bool
+	senders  sync.WaitGroup
+	stopping chan struct{}
+	done     chan struct{}
+	restarts atomic.Int64
+}
+
+// Spawn starts a new actor with a mailbox of the given capacity.
+// If strategy is nil the actor is restarted on every panic.
+func Spawn[M any, R any](factory func() functional.Function[M, R], mailboxSize int, strategy Strategy) *Actor[M, R] {
+	if strategy == nil {
+		strategy = RestartOnPanic()
+	}
+	actor := &Actor[M, R]{
+		factory:  factory,
+		strategy: strategy,
+		mailbox:  make(chan envelope[M, R], mailboxSize),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go actor.run()
+	return actor
+}
+
+// Tell sends a message without waiting for its processing, blocking only while the mailbox is full
+func (a *Actor[M, R]) Tell(msg M) error {
+	return a.enqueue(envelope[M, R]{msg: msg}, true)
+}
+
+// TryTell sends a message without blocking, failing with ErrMailboxFull if the mailbox is full
+func (a *Actor[M, R]) TryTell(msg M) error {
+	return a.enqueue(envelope[M, R]{msg: msg}, false)
+}
+
+// Ask sends a message and returns a Promise of the reply, already computing
+func (a *Actor[M, R]) Ask(msg M) *functional.Promise[R] {
+	return a.ask(msg, true)
+}
+
+// TryAsk is the non-blocking version of Ask: the Promise fails with ErrMailboxFull if the mailbox is full
+func (a *Actor[M, R]) TryAsk(msg M) *functional.Promise[R] {
+	return a.ask(msg, false)
+}
+
+func (a *Actor[M, R]) ask(msg M, block bool) *functional.Promise[R] {
+	reply := make(chan functional.Either[R], 1)
+	err := a.enqueue(envelope[M, R]{msg: msg, reply: reply}, block)
+	return functional.ComputeAsync(func() (*R, error) {
+		if err != nil {
+			return nil, err
+		}
+		return (<-reply).Get()
+	})
+}
+
+func (a *Actor[M, R]) enqueue(env envelope[M, R], block bool) error {
+	a.mu.RLock()
+	if a.stopped {
+		a.mu.RUnlock()
+		return ErrActorStopped
+	}
+	a.senders.Add(1)
+	a.mu.RUnlock()
+	defer a.senders.Done()
+
+	if !block {
+		select {
+		case a.mailbox <- env:
+			return nil
+		default:
+			return ErrMailboxFull
+		}
+	}
+	select {
+	case a.mailbox <- env:
+		return nil
+	case <-a.stopping:
+		return ErrActorStopped
+	}
+}
+
+// Stop gracefully stops the actor: new messages are refused while the ones already in the mailbox are processed.
+// It returns once the mailbox has been drained.
+func (a *Actor[M, R]) Stop() {
+	a.close()
+	<-a.done
+}
+
+// Done returns a channel which is closed when the actor has terminated
+func (a *Actor[M, R]) Done() <-chan struct{} {
+	return a.done
+}
+
+// Restarts returns how many times the actor has been restarted after a panic
+func (a *Actor[M, R]) Restarts() int {
+	return int(a.restarts.Load())
+}
+
+// close refuses any further message, unblocking the senders waiting on a full mailbox
+func (a *Actor[M, R]) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.stopped {
+		a.stopped = true
+		close(a.stopping)
+	}
+}
+
+func (a *Actor[M, R]) run() {
+	defer close(a.done)
+	behavior := a.factory()
+	for {
+		select {
+		case env := <-a.mailbox:
+			if !a.handle(&behavior, env) {
+				a.fail()
+				return
+			}
+		case <-a.stopping:
+			// the messages sent before the stop are still processed
+			a.senders.Wait()
+			for {
+				select {
+				case env := <-a.mailbox:
+					if !a.handle(&behavior, env) {
+						a.fail()
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle processes a message applying the strategy after a panic, returning false if the actor must stop
+func (a *Actor[M, R]) handle(behavior *functional.Function[M, R], env envelope[M, R]) bool {
+	panicErr := a.process(*behavior, env)
+	if panicErr == nil {
+		return true
+	}
+	switch a.strategy(panicErr, a.Restarts()) {
+	case Restart:
+		a.restarts.Add(1)
+		*behavior = a.factory()
+	case Stop:
+		return false
+	}
+	return true
+}
+
+// fail stops the actor after a Stop directive, failing the messages left in the mailbox
+func (a *Actor[M, R]) fail() {
+	a.close()
+	a.senders.Wait()
+	for {
+		select {
+		case env := <-a.mailbox:
+			reply(env, functional.EitherFromError[R](ErrActorStopped))
+		default:
+			return
+		}
+	}
+}
+
+func (a *Actor[M, R]) process(behavior functional.Function[M, R], env envelope[M, R]) (panicErr *PanicError) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = &PanicError{Value: r}
+			reply(env, functional.EitherFromError[R](panicErr))
+		}
+	}()
+	result, err := behavior(env.msg)
+	if err != nil {
+		reply(env, functional.EitherFromError[R](err))
+		return nil
+	}
+	reply(env, functional.EitherFromResult(result))
+	return nil
+}
+
+func reply[M any, R any](env envelope[M, R], either functional.Either[R]) {
+	if env.reply != nil {
+		env.reply <- either
+	}
+}
@@ -0,0 +1,205 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gyozatech/sushi/functional"
+)
+
+type command struct {
+	op     string
+	amount int
+}
+
+// counter is a behavior owning an integer state
+func counter() functional.Function[command, int] {
+	total := 0
+	return func(cmd command) (*int, error) {
+		switch cmd.op {
+		case "add":
+			total += cmd.amount
+		case "panic":
+			panic("boom")
+		case "fail":
+			return nil, fmt.Errorf("failed")
+		}
+		value := total
+		return &value, nil
+	}
+}
+
+func TestActorTellAndAsk(t *testing.T) {
+	testName := "TestActorTellAndAsk"
+	actor := Spawn(counter, 10, nil)
+	defer actor.Stop()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := actor.Tell(command{op: "add", amount: 1}); err != nil {
+				t.Errorf("%s failed: unexpected error %s", testName, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result, err := actor.Ask(command{op: "get"}).WaitForResult().Get()
+	if err != nil || *result != 100 {
+		t.Errorf("%s failed: expected 100, got %v (%v)", testName, result, err)
+	}
+	if err := actor.Ask(command{op: "fail"}).WaitForResult().GetError(); err == nil || err.Error() != "failed" {
+		t.Errorf("%s failed: expected the behavior error, got %v", testName, err)
+	}
+}
+
+func TestActorSupervision(t *testing.T) {
+	testName := "TestActorSupervision"
+
+	testCases := []struct {
+		description   string
+		strategy      Strategy
+		expectedTotal int
+		expectedErr   error
+	}{
+		{
+			description:   "Restart resets the state",
+			strategy:      RestartOnPanic(),
+			expectedTotal: 0,
+		},
+		{
+			description:   "Resume keeps the state",
+			strategy:      ResumeOnPanic(),
+			expectedTotal: 5,
+		},
+		{
+			description: "Stop refuses further messages",
+			strategy:    StopOnPanic(),
+			expectedErr: ErrActorStopped,
+		},
+	}
+	for _, testCase := range testCases {
+		actor := Spawn(counter, 10, testCase.strategy)
+		_ = actor.Tell(command{op: "add", amount: 5})
+
+		var panicErr *PanicError
+		if err := actor.Ask(command{op: "panic"}).WaitForResult().GetError(); !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+			t.Errorf("%s failed (%s): expected a PanicError, got %v", testName, testCase.description, err)
+		}
+
+		result, err := actor.Ask(command{op: "get"}).WaitForResult().Get()
+		if testCase.expectedErr != nil {
+			if !errors.Is(err, testCase.expectedErr) {
+				t.Errorf("%s failed (%s): expected error %s, got %v", testName, testCase.description, testCase.expectedErr, err)
+			}
+		} else if err != nil || *result != testCase.expectedTotal {
+			t.Errorf("%s failed (%s): expected %d, got %v (%v)", testName, testCase.description, testCase.expectedTotal, result, err)
+		}
+		actor.Stop()
+	}
+}
+
+func TestActorRestartAtMost(t *testing.T) {
+	testName := "TestActorRestartAtMost"
+	actor := Spawn(counter, 10, RestartAtMost(2))
+
+	for i := 0; i < 3; i++ {
+		actor.Ask(command{op: "panic"}).WaitForResult()
+	}
+	select {
+	case <-actor.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("%s failed: the actor must stop after exceeding the restarts", testName)
+	}
+	if actor.Restarts() != 2 {
+		t.Errorf("%s failed: expected 2 restarts, got %d", testName, actor.Restarts())
+	}
+	if err := actor.Tell(command{op: "get"}); !errors.Is(err, ErrActorStopped) {
+		t.Errorf("%s failed: expected ErrActorStopped, got %v", testName, err)
+	}
+}
+
+func TestActorGracefulStop(t *testing.T) {
+	testName := "TestActorGracefulStop"
+	release := make(chan struct{})
+	processed := 0
+	slow := func() functional.Function[int, int] {
+		return func(i int) (*int, error) {
+			<-release
+			processed++
+			value := processed
+			return &value, nil
+		}
+	}
+	actor := Spawn(slow, 5, nil)
+
+	promises := []*functional.Promise[int]{}
+	for i := 0; i < 5; i++ {
+		promises = append(promises, actor.Ask(i))
+	}
+	if err := actor.TryTell(5); !errors.Is(err, ErrMailboxFull) {
+		// the first message may already be in progress, leaving room for one more
+		if err != nil {
+			t.Errorf("%s failed: unexpected error %v", testName, err)
+		}
+	}
+
+	close(release)
+	actor.Stop()
+
+	for i, promise := range promises {
+		if result, err := promise.WaitForResult().Get(); err != nil || *result != i+1 {
+			t.Errorf("%s failed: expected message %d to be processed, got %v (%v)", testName, i, result, err)
+		}
+	}
+	if err := actor.Tell(6); !errors.Is(err, ErrActorStopped) {
+		t.Errorf("%s failed: expected ErrActorStopped after Stop, got %v", testName, err)
+	}
+}
+
+func TestActorRestartWithBlockedSender(t *testing.T) {
+	testName := "TestActorRestartWithBlockedSender"
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blocking := func() functional.Function[string, int] {
+		return func(msg string) (*int, error) {
+			if msg == "panic" {
+				close(started)
+				<-release
+				panic("boom")
+			}
+			value := 0
+			return &value, nil
+		}
+	}
+	actor := Spawn(blocking, 1, RestartOnPanic())
+
+	_ = actor.Tell("panic")
+	<-started
+	_ = actor.Tell("fill")
+	sent := make(chan error)
+	go func() {
+		// blocks on the full mailbox while the actor restarts
+		sent <- actor.Tell("blocked")
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Errorf("%s failed: unexpected error %v", testName, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("%s failed: the sender is deadlocked with the restart", testName)
+	}
+	actor.Stop()
+	if actor.Restarts() != 1 {
+		t.Errorf("%s failed: expected 1 restart, got %d", testName, actor.Restarts())
+	}
+}
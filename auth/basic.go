@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// UsernameKey is the context key of the username authenticated by BasicAuth
+var UsernameKey = utils.NewContextKey[string]("username")
+
+// Username returns the username authenticated by BasicAuth
+func Username(ctx context.Context) (string, bool) {
+	return UsernameKey.Get(ctx)
+}
+
+// Verifier checks the credentials of a user: an error means they couldn't be checked, e.g. the database is unreachable
+type Verifier func(ctx context.Context, username, password string) (bool, error)
+
+// StaticVerifier accepts a single pair of credentials, comparing them in constant time
+func StaticVerifier(username, password string) Verifier {
+	expectedUsername, expectedPassword := sha256.Sum256([]byte(username)), sha256.Sum256([]byte(password))
+	return func(ctx context.Context, u, p string) (bool, error) {
+		// the hashes have the same length, so the comparison time doesn't depend on the length of the credentials
+		actualUsername, actualPassword := sha256.Sum256([]byte(u)), sha256.Sum256([]byte(p))
+		usernameMatch := subtle.ConstantTimeCompare(actualUsername[:], expectedUsername[:])
+		passwordMatch := subtle.ConstantTimeCompare(actualPassword[:], expectedPassword[:])
+		return usernameMatch&passwordMatch == 1, nil
+	}
+}
+
+// BasicOption customizes BasicAuth
+type BasicOption func(*basicAuth)
+
+// WithRealm sets the realm sent to the clients in the WWW-Authenticate header ("Restricted" by default)
+func WithRealm(realm string) BasicOption {
+	return func(b *basicAuth) {
+		b.realm = realm
+	}
+}
+
+type basicAuth struct {
+	realm string
+}
+
+// BasicAuth authenticates the requests with the HTTP Basic authentication scheme (RFC 7617), storing the username in the context.
+// The requests without valid credentials are rejected with 401 Unauthorized and a WWW-Authenticate challenge,
+// the ones whose credentials cannot be verified with 500 Internal Server Error.
+func BasicAuth(next http.Handler, verify Verifier, options ...BasicOption) http.Handler {
+	b := &basicAuth{realm: "Restricted"}
+	for _, option := range options {
+		option(b)
+	}
+	challenge := `Basic realm="` + quote(b.realm) + `", charset="UTF-8"`
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, err := utils.GetBasicToken(r)
+		if err == nil {
+			var ok bool
+			ok, err = verify(r.Context(), username, password)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if ok {
+				next.ServeHTTP(w, r.WithContext(UsernameKey.With(r.Context(), username)))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", challenge)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
+}
+
+// quote escapes the content of a quoted-string
+func quote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gyozatech/sushi/password"
+)
+
+func TestMain(m *testing.M) {
+	// cheap hashes keep the tests fast
+	password.DefaultHasher = password.Scrypt{LogN: 8}
+	m.Run()
+}
+
+func TestBasicAuth(t *testing.T) {
+	var authenticated string
+	handler := BasicAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated, _ = Username(r.Context())
+	}), func(ctx context.Context, username, password string) (bool, error) {
+		if username == "broken" {
+			return false, errors.New("database unreachable")
+		}
+		return StaticVerifier("alice", "s3:cr\"et")(ctx, username, password)
+	}, WithRealm(`Admin "area"`))
+
+	tests := []struct {
+		testName string
+		username string
+		password string
+		header   bool
+		expected int
+	}{
+		{"valid credentials", "alice", "s3:cr\"et", true, http.StatusOK},
+		{"wrong password", "alice", "secret", true, http.StatusUnauthorized},
+		{"wrong username", "bob", "s3:cr\"et", true, http.StatusUnauthorized},
+		{"missing credentials", "", "", false, http.StatusUnauthorized},
+		{"verifier error", "broken", "secret", true, http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		authenticated = ""
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.header {
+			request.SetBasicAuth(test.username, test.password)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != test.expected {
+			t.Errorf("%s failed (%s): expected %d, got %d", t.Name(), test.testName, test.expected, recorder.Code)
+		}
+		challenge := recorder.Header().Get("WWW-Authenticate")
+		if test.expected == http.StatusUnauthorized && challenge != `Basic realm="Admin \"area\"", charset="UTF-8"` {
+			t.Errorf("%s failed (%s): unexpected challenge %s", t.Name(), test.testName, challenge)
+		}
+		if test.expected == http.StatusOK && authenticated != test.username {
+			t.Errorf("%s failed (%s): expected %s in the context, got %s", t.Name(), test.testName, test.username, authenticated)
+		}
+	}
+}
+
+func TestHtpasswd(t *testing.T) {
+	store := NewHtpasswd()
+	if err := store.Set("alice", "wonderland"); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if err := store.Set("bad:name", "secret"); err == nil {
+		t.Errorf("%s failed: expected an error for a username with a colon", t.Name())
+	}
+	var file bytes.Buffer
+	if _, err := store.WriteTo(&file); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	// {SHA} hash of "password", as written by htpasswd -s
+	file.WriteString("# legacy users\n\nbob:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n")
+
+	loaded, err := LoadHtpasswd(&file)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	tests := []struct {
+		username string
+		password string
+		expected bool
+	}{
+		{"alice", "wonderland", true},
+		{"alice", "Wonderland", false},
+		{"bob", "password", true},
+		{"bob", "passw0rd", false},
+		{"carol", "wonderland", false},
+	}
+	for _, test := range tests {
+		if ok, err := loaded.Verify(context.Background(), test.username, test.password); ok != test.expected || err != nil {
+			t.Errorf("%s failed (%s:%s): expected %t, got %t (%v)", t.Name(), test.username, test.password, test.expected, ok, err)
+		}
+	}
+
+	loaded.Delete("alice")
+	if ok, _ := loaded.Verify(context.Background(), "alice", "wonderland"); ok {
+		t.Errorf("%s failed: expected the deleted user to be rejected", t.Name())
+	}
+}
+
+func TestLoadHtpasswdErrors(t *testing.T) {
+	tests := []struct {
+		testName string
+		file     string
+		expected error
+	}{
+		{"bcrypt", "alice:$2y$05$abcdefghijklmnopqrstuu\n", ErrUnsupportedHash},
+		{"apr1", "alice:$apr1$salt$hash\n", ErrUnsupportedHash},
+		{"missing colon", "alice\n", nil},
+	}
+	for _, test := range tests {
+		_, err := LoadHtpasswd(strings.NewReader(test.file))
+		if err == nil || (test.expected != nil && !errors.Is(err, test.expected)) {
+			t.Errorf("%s failed (%s): expected error %v, got %v", t.Name(), test.testName, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gyozatech/sushi/password"
+)
+
+// ErrUnsupportedHash is returned when loading htpasswd entries whose hashes cannot be verified, e.g. bcrypt or MD5
+var ErrUnsupportedHash = errors.New("auth: unsupported password hash")
+
+// Htpasswd is an htpasswd-style store of credentials: one username:hash entry per line, where the hash is a PHC string
+// of the password package (e.g. $scrypt$...) or a legacy {SHA} hash. It's safe for concurrent use.
+type Htpasswd struct {
+	mu     sync.RWMutex
+	hashes map[string]string
+}
+
+// NewHtpasswd returns an empty store
+func NewHtpasswd() *Htpasswd {
+	return &Htpasswd{hashes: map[string]string{}}
+}
+
+// LoadHtpasswd reads a store: empty lines and lines starting with # are ignored
+func LoadHtpasswd(r io.Reader) (*Htpasswd, error) {
+	h := NewHtpasswd()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		username, hash, ok := strings.Cut(entry, ":")
+		if !ok || username == "" {
+			return nil, fmt.Errorf("auth: malformed htpasswd entry at line %d", line)
+		}
+		if !strings.HasPrefix(hash, "{SHA}") && !strings.HasPrefix(hash, "$scrypt$") && !strings.HasPrefix(hash, "$pbkdf2-sha256$") {
+			return nil, fmt.Errorf("%w for %s at line %d", ErrUnsupportedHash, username, line)
+		}
+		h.hashes[username] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("auth: cannot read the htpasswd entries: %w", err)
+	}
+	return h, nil
+}
+
+// ReadHtpasswdFile reads a store from a file
+func ReadHtpasswdFile(path string) (*Htpasswd, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadHtpasswd(f)
+}
+
+// Set adds or replaces the credentials of a user, hashing the password with password.Hash
+func (h *Htpasswd) Set(username, plain string) error {
+	if username == "" || strings.ContainsAny(username, ":\n") {
+		return fmt.Errorf("auth: invalid username %q", username)
+	}
+	hash, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.hashes[username] = hash
+	return nil
+}
+
+// Delete removes the credentials of a user
+func (h *Htpasswd) Delete(username string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.hashes, username)
+}
+
+// WriteTo writes the entries sorted by username
+func (h *Htpasswd) WriteTo(w io.Writer) (int64, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	usernames := make([]string, 0, len(h.hashes))
+	for username := range h.hashes {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	var written int64
+	for _, username := range usernames {
+		n, err := fmt.Fprintf(w, "%s:%s\n", username, h.hashes[username])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Verify checks the credentials of a user: it's a Verifier for BasicAuth.
+// The unknown users take as long as the known ones, so that they cannot be told apart by timing the responses.
+func (h *Htpasswd) Verify(ctx context.Context, username, plain string) (bool, error) {
+	h.mu.RLock()
+	hash, ok := h.hashes[username]
+	h.mu.RUnlock()
+	if !ok {
+		dummy, err := dummyHash()
+		if err == nil {
+			_, _ = password.Verify(plain, dummy)
+		}
+		return false, nil
+	}
+	if legacy, isSHA := strings.CutPrefix(hash, "{SHA}"); isSHA {
+		sum := sha1.Sum([]byte(plain))
+		return subtle.ConstantTimeCompare([]byte(base64.StdEncoding.EncodeToString(sum[:])), []byte(legacy)) == 1, nil
+	}
+	return password.Verify(plain, hash)
+}
+
+var dummy struct {
+	once sync.Once
+	hash string
+	err  error
+}
+
+// dummyHash returns the hash verified for the unknown users
+func dummyHash() (string, error) {
+	dummy.once.Do(func() {
+		dummy.hash, dummy.err = password.Hash("dummy password")
+	})
+	return dummy.hash, dummy.err
+}
@@ -0,0 +1,161 @@
+package baggage
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// the limits of the W3C Baggage specification
+const (
+	DefaultMaxMembers = 64
+	DefaultMaxBytes   = 8192
+)
+
+var (
+	// ErrMalformed is returned for the baggage headers which don't follow the W3C Baggage syntax
+	ErrMalformed = errors.New("baggage: malformed header")
+	// ErrTooLarge is returned for the baggage headers exceeding the member or the size limits
+	ErrTooLarge = errors.New("baggage: header too large")
+)
+
+// Member is an entry of the baggage: its properties (e.g. ;ttl=10) are kept in their encoded form
+type Member struct {
+	Key        string
+	Value      string
+	Properties string
+}
+
+// String encodes the member, percent-encoding its value
+func (m Member) String() string {
+	s := m.Key + "=" + encodeValue(m.Value)
+	if m.Properties != "" {
+		s += ";" + m.Properties
+	}
+	return s
+}
+
+// Parse decodes the values of the baggage headers of a request, which are joined as a single list:
+// it fails if the list has more than maxMembers members or more than maxBytes bytes
+func Parse(headers []string, maxMembers, maxBytes int) ([]Member, error) {
+	header := strings.Join(headers, ",")
+	if len(header) > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes, the limit is %d", ErrTooLarge, len(header), maxBytes)
+	}
+	members := []Member{}
+	if strings.Trim(header, " \t") == "" {
+		return members, nil
+	}
+	for _, entry := range strings.Split(header, ",") {
+		if len(members) == maxMembers {
+			return nil, fmt.Errorf("%w: more than %d members", ErrTooLarge, maxMembers)
+		}
+		member, err := parseMember(entry)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// Format encodes the members as the value of a baggage header
+func Format(members []Member) string {
+	entries := make([]string, len(members))
+	for i, member := range members {
+		entries[i] = member.String()
+	}
+	return strings.Join(entries, ",")
+}
+
+// parseMember parses key=value(;property)* with optional whitespace around the keys, the values and the properties
+func parseMember(entry string) (Member, error) {
+	keyValue, properties, _ := strings.Cut(entry, ";")
+	k, v, ok := strings.Cut(keyValue, "=")
+	k, v = trim(k), trim(v)
+	if !ok || !isToken(k) {
+		return Member{}, fmt.Errorf("%w: invalid member %q", ErrMalformed, entry)
+	}
+	value, err := decodeValue(v)
+	if err != nil {
+		return Member{}, fmt.Errorf("%w: invalid value of %q", ErrMalformed, k)
+	}
+	member := Member{Key: k, Value: value}
+	if properties != "" {
+		parsed := []string{}
+		for _, property := range strings.Split(properties, ";") {
+			pk, pv, hasValue := strings.Cut(property, "=")
+			pk, pv = trim(pk), trim(pv)
+			if !isToken(pk) {
+				return Member{}, fmt.Errorf("%w: invalid property %q of %q", ErrMalformed, property, k)
+			}
+			if !hasValue {
+				parsed = append(parsed, pk)
+				continue
+			}
+			if _, err := decodeValue(pv); err != nil {
+				return Member{}, fmt.Errorf("%w: invalid property %q of %q", ErrMalformed, property, k)
+			}
+			parsed = append(parsed, pk+"="+pv)
+		}
+		member.Properties = strings.Join(parsed, ";")
+	}
+	return member, nil
+}
+
+func trim(s string) string {
+	return strings.Trim(s, " \t")
+}
+
+// isToken checks the characters of the keys: RFC 7230 tokens
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0 {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// isBaggageOctet checks the characters allowed unencoded in the values:
+// the printable ASCII characters except the double quote, the comma, the semicolon, the backslash and the percent sign
+func isBaggageOctet(c byte) bool {
+	return c > 0x20 && c < 0x7f && c != '"' && c != ',' && c != ';' && c != '\\' && c != '%'
+}
+
+// encodeValue percent-encodes the UTF-8 bytes of the value which aren't baggage octets
+func encodeValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; isBaggageOctet(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// decodeValue decodes a percent-encoded value: the invalid UTF-8 sequences are replaced by U+FFFD as the specification requires
+func decodeValue(value string) (string, error) {
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' && !isBaggageOctet(value[i]) {
+			return "", ErrMalformed
+		}
+	}
+	decoded, err := url.PathUnescape(value)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.ValidString(decoded) {
+		decoded = strings.ToValidUTF8(decoded, "�")
+	}
+	return decoded, nil
+}
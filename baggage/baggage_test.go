@@ -0,0 +1,154 @@
+package baggage
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		testName string
+		headers  []string
+		expected []Member
+		err      error
+	}{
+		{"empty", []string{}, []Member{}, nil},
+		{"single member", []string{"userId=alice"}, []Member{{Key: "userId", Value: "alice"}}, nil},
+		{"whitespace and properties", []string{" userId = alice ; ttl = 10;secret , tenant=acme"},
+			[]Member{{Key: "userId", Value: "alice", Properties: "ttl=10;secret"}, {Key: "tenant", Value: "acme"}}, nil},
+		{"percent-encoded value", []string{"name=Jos%C3%A9%2C%20Jr%3B=%25"}, []Member{{Key: "name", Value: "José, Jr;=%"}}, nil},
+		{"invalid UTF-8 replaced", []string{"name=a%FFb"}, []Member{{Key: "name", Value: "a�b"}}, nil},
+		{"empty value", []string{"flag="}, []Member{{Key: "flag", Value: ""}}, nil},
+		{"multiple headers", []string{"a=1", "b=2"}, []Member{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}, nil},
+		{"missing value", []string{"userId"}, nil, ErrMalformed},
+		{"invalid key", []string{"user id=alice"}, nil, ErrMalformed},
+		{"empty member", []string{"a=1,,b=2"}, nil, ErrMalformed},
+		{"unencoded space", []string{"name=John Doe"}, nil, ErrMalformed},
+		{"invalid escape", []string{"name=100%"}, nil, ErrMalformed},
+		{"invalid property", []string{"a=1;=2"}, nil, ErrMalformed},
+		{"too many members", []string{"a=1,b=2,c=3"}, nil, ErrTooLarge},
+		{"too many bytes", []string{"a=" + strings.Repeat("x", 100)}, nil, ErrTooLarge},
+	}
+	for _, test := range tests {
+		members, err := Parse(test.headers, 2, 64)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s failed (%s): expected error %v, got %v", t.Name(), test.testName, test.err, err)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(members, test.expected) {
+			t.Errorf("%s failed (%s): expected %+v, got %+v", t.Name(), test.testName, test.expected, members)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	members := []Member{{Key: "name", Value: "José, Jr;=%"}, {Key: "tenant", Value: "acme", Properties: "ttl=10"}}
+	formatted := Format(members)
+	if expected := "name=Jos%C3%A9%2C%20Jr%3B=%25,tenant=acme;ttl=10"; formatted != expected {
+		t.Errorf("%s failed: expected %s, got %s", t.Name(), expected, formatted)
+	}
+	parsed, err := Parse([]string{formatted}, DefaultMaxMembers, DefaultMaxBytes)
+	if err != nil || !reflect.DeepEqual(parsed, members) {
+		t.Errorf("%s failed: expected %+v, got %+v (%v)", t.Name(), members, parsed, err)
+	}
+}
+
+type tenant struct {
+	ID   int
+	Name string
+}
+
+func TestPropagation(t *testing.T) {
+	propagator, err := New([]string{"user-id", "tenant", "attempt", "locale"})
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+
+	var received map[string]interface{}
+	var header string
+	server := httptest.NewServer(propagator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = utils.FetchContextValues(r.Context())
+		header = r.Header.Get(Header)
+	})))
+	defer server.Close()
+
+	ctx := utils.EnrichContextWithValue(context.Background(), "user-id", "alice smith")
+	ctx = utils.EnrichContextWithValue(ctx, "tenant", tenant{ID: 7, Name: "acme"})
+	ctx = utils.EnrichContextWithValue(ctx, "attempt", 2)
+	ctx = utils.EnrichContextWithValue(ctx, "password", "secret")
+
+	client := &http.Client{Transport: propagator.Transport(nil)}
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	request.Header.Set(Header, "user-id=bob,trace=abc")
+	response, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	response.Body.Close()
+
+	expected := map[string]interface{}{"user-id": "alice smith", "tenant": `{"ID":7,"Name":"acme"}`, "attempt": "2"}
+	if !reflect.DeepEqual(received, expected) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), expected, received)
+	}
+	if expectedHeader := "trace=abc,user-id=alice%20smith,tenant={%22ID%22:7%2C%22Name%22:%22acme%22},attempt=2"; header != expectedHeader {
+		t.Errorf("%s failed: expected header %s, got %s", t.Name(), expectedHeader, header)
+	}
+	if request.Header.Get(Header) != "user-id=bob,trace=abc" {
+		t.Errorf("%s failed: the transport modified the original request: %s", t.Name(), request.Header.Get(Header))
+	}
+}
+
+func TestInjectLimits(t *testing.T) {
+	propagator, _ := New([]string{"a", "b", "c"}, WithMaxMembers(2), WithMaxBytes(12))
+	ctx := utils.EnrichContextWithValue(context.Background(), "a", "1")
+	ctx = utils.EnrichContextWithValue(ctx, "b", "too long to fit")
+	ctx = utils.EnrichContextWithValue(ctx, "c", "3")
+
+	header := http.Header{}
+	propagator.Inject(ctx, header)
+	if value := header.Get(Header); value != "a=1,c=3" {
+		t.Errorf("%s failed: expected a=1,c=3, got %s", t.Name(), value)
+	}
+
+	header = http.Header{Header: []string{"malformed"}}
+	propagator.Inject(context.Background(), header)
+	if values := header.Values(Header); len(values) != 0 {
+		t.Errorf("%s failed: expected the malformed header to be dropped, got %v", t.Name(), values)
+	}
+}
+
+func TestMiddlewareRejections(t *testing.T) {
+	propagator, _ := New([]string{"a"}, WithMaxMembers(2))
+	handler := propagator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tests := []struct {
+		testName string
+		header   string
+		expected int
+	}{
+		{"valid", "a=1,b=2", http.StatusOK},
+		{"malformed", "a=1,b", http.StatusBadRequest},
+		{"too large", "a=1,b=2,c=3", http.StatusRequestHeaderFieldsTooLarge},
+	}
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(Header, test.header)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != test.expected {
+			t.Errorf("%s failed (%s): expected %d, got %d", t.Name(), test.testName, test.expected, recorder.Code)
+		}
+	}
+}
+
+func TestNewInvalidKey(t *testing.T) {
+	if _, err := New([]string{"user id"}); err == nil {
+		t.Errorf("%s failed: expected an error for an invalid key", t.Name())
+	}
+}
@@ -0,0 +1,166 @@
+package baggage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// Header is the name of the W3C Baggage HTTP header
+const Header = "baggage"
+
+// Option customizes a Propagator
+type Option func(*Propagator)
+
+// WithMaxMembers sets the maximum number of members of the baggage (64 by default)
+func WithMaxMembers(n int) Option {
+	return func(p *Propagator) {
+		p.maxMembers = n
+	}
+}
+
+// WithMaxBytes sets the maximum size of the baggage in bytes (8192 by default)
+func WithMaxBytes(n int) Option {
+	return func(p *Propagator) {
+		p.maxBytes = n
+	}
+}
+
+// Propagator propagates an allow-list of the context values set with utils.EnrichContextWithValue
+// (or a utils.ContextKey) across HTTP calls through the baggage header
+type Propagator struct {
+	keys       []string
+	allowed    map[string]bool
+	maxMembers int
+	maxBytes   int
+}
+
+// New returns a Propagator of the context values with the given names, which must be valid baggage keys
+func New(keys []string, options ...Option) (*Propagator, error) {
+	p := &Propagator{allowed: map[string]bool{}, maxMembers: DefaultMaxMembers, maxBytes: DefaultMaxBytes}
+	for _, option := range options {
+		option(p)
+	}
+	for _, key := range keys {
+		if !isToken(key) {
+			return nil, fmt.Errorf("baggage: invalid key %q", key)
+		}
+		if !p.allowed[key] {
+			p.keys = append(p.keys, key)
+			p.allowed[key] = true
+		}
+	}
+	return p, nil
+}
+
+// Inject writes the allow-listed context values in the baggage header, after the members already in the header
+// for other keys. The values are formatted as strings (fmt.Stringer or JSON for the non-string values)
+// and the members exceeding the limits are dropped. A malformed baggage header is replaced.
+func (p *Propagator) Inject(ctx context.Context, header http.Header) {
+	values := utils.FetchContextValues(ctx)
+	members, err := Parse(header.Values(Header), p.maxMembers, p.maxBytes)
+	if err != nil {
+		members = []Member{}
+	}
+	kept := members[:0]
+	for _, member := range members {
+		if _, overridden := values[member.Key]; !overridden || !p.allowed[member.Key] {
+			kept = append(kept, member)
+		}
+	}
+	members = kept
+
+	size := len(Format(members))
+	for _, key := range p.keys {
+		value, ok := values[key]
+		if !ok || len(members) == p.maxMembers {
+			continue
+		}
+		member := Member{Key: key, Value: format(value)}
+		length := len(member.String())
+		if size > 0 {
+			length++ // comma
+		}
+		if size+length > p.maxBytes {
+			continue
+		}
+		members = append(members, member)
+		size += length
+	}
+
+	header.Del(Header)
+	if len(members) > 0 {
+		header.Set(Header, Format(members))
+	}
+}
+
+// Extract returns a child context with the allow-listed members of the baggage header as string context values:
+// the other members are ignored. It fails for malformed headers and headers exceeding the limits.
+func (p *Propagator) Extract(ctx context.Context, header http.Header) (context.Context, error) {
+	members, err := Parse(header.Values(Header), p.maxMembers, p.maxBytes)
+	if err != nil {
+		return ctx, err
+	}
+	for _, member := range members {
+		if p.allowed[member.Key] {
+			ctx = utils.EnrichContextWithValue(ctx, member.Key, member.Value)
+		}
+	}
+	return ctx, nil
+}
+
+// Transport returns an http.RoundTripper which injects the baggage in the requests before sending them
+// with the base RoundTripper (http.DefaultTransport if nil)
+func (p *Propagator) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripper(func(r *http.Request) (*http.Response, error) {
+		// a RoundTripper must not modify the request
+		r = r.Clone(r.Context())
+		p.Inject(r.Context(), r.Header)
+		return base.RoundTrip(r)
+	})
+}
+
+// Middleware restores the baggage of the incoming requests in their context. The requests with malformed baggage
+// are rejected with 400 Bad Request, the ones with baggage exceeding the limits with 431 Request Header Fields Too Large.
+func (p *Propagator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := p.Extract(r.Context(), r.Header)
+		if errors.Is(err, ErrTooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestHeaderFieldsTooLarge)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// format converts a context value to the string propagated in the baggage
+func format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,60 @@
+package channels
+
+import "context"
+
+// DropPolicy defines how Buffer behaves when the buffer is full
+type DropPolicy int
+
+const (
+	// Block stops reading from the input channel until there is room in the buffer
+	Block DropPolicy = iota
+	// DropNewest discards the incoming values while the buffer is full
+	DropNewest
+	// DropOldest discards the oldest buffered value to make room for the incoming one
+	DropOldest
+)
+
+// Buffer decouples a producer from a slow consumer by buffering up to size values according to the given policy.
+// The values left in the buffer are still delivered after the input channel is closed.
+// A size <= 0 means no buffer: the values are passed through as with OrDone, whatever the policy.
+func Buffer[T any](ctx context.Context, in <-chan T, size int, policy DropPolicy) <-chan T {
+	if size <= 0 {
+		return OrDone(ctx, in)
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		queue := make([]T, 0, size)
+		input := in
+		for input != nil || len(queue) > 0 {
+			// a nil channel is never selected: this disables reading when blocked and writing when empty
+			var output chan T
+			var next T
+			if len(queue) > 0 {
+				output, next = out, queue[0]
+			}
+			reading := input
+			if policy == Block && len(queue) >= size {
+				reading = nil
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case output <- next:
+				queue = queue[1:]
+			case v, ok := <-reading:
+				if !ok {
+					input = nil
+					continue
+				}
+				switch {
+				case len(queue) < size:
+					queue = append(queue, v)
+				case policy == DropOldest && len(queue) > 0:
+					queue = append(queue[1:], v)
+				}
+			}
+		}
+	}()
+	return out
+}
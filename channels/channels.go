@@ -0,0 +1,127 @@
+package channels
+
+import (
+	"context"
+	"sync"
+)
+
+// OrDone relays the values of a channel until either the channel is closed or the context is cancelled
+func OrDone[T any](ctx context.Context, in <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := receive(ctx, in)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Merge fans in multiple channels into a single one, which is closed once all of them are closed
+func Merge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	wg := sync.WaitGroup{}
+	wg.Add(len(chans))
+	for _, ch := range chans {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for {
+				v, ok := receive(ctx, ch)
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Tee duplicates every value of a channel into two channels.
+// Each value is delivered to both outputs before the next one is read, so the slowest reader sets the pace.
+func Tee[T any](ctx context.Context, in <-chan T) (<-chan T, <-chan T) {
+	outs := Broadcast(ctx, in, 2)
+	return outs[0], outs[1]
+}
+
+// Broadcast fans out every value of a channel to n channels.
+// Each value is delivered to all the outputs before the next one is read, so the slowest reader sets the pace.
+func Broadcast[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			for _, out := range outs {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}
+	}()
+	return result
+}
+
+// FromSlice emits the elements of a slice on a channel, which is closed after the last one
+func FromSlice[T any](ctx context.Context, slice []T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range slice {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// ToSlice collects the values of a channel until it's closed or the context is cancelled
+func ToSlice[T any](ctx context.Context, in <-chan T) []T {
+	result := []T{}
+	for {
+		v, ok := receive(ctx, in)
+		if !ok {
+			return result
+		}
+		result = append(result, v)
+	}
+}
+
+// receive reads a value from a channel, returning false if the channel is closed or the context is cancelled
+func receive[T any](ctx context.Context, in <-chan T) (T, bool) {
+	select {
+	case <-ctx.Done():
+		return *new(T), false
+	case v, ok := <-in:
+		return v, ok
+	}
+}
+
+// send writes a value to a channel, returning false if the context is cancelled before the value is accepted
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- v:
+		return true
+	}
+}
@@ -0,0 +1,90 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestFromSliceToSlice(t *testing.T) {
+	testName := "TestFromSliceToSlice"
+	ctx := context.TODO()
+
+	input := []int{1, 2, 3, 4, 5}
+	if actual := ToSlice(ctx, FromSlice(ctx, input)); !reflect.DeepEqual(input, actual) {
+		t.Errorf("%s failed: expected %v, got %v", testName, input, actual)
+	}
+	if actual := ToSlice(ctx, FromSlice[int](ctx, nil)); len(actual) != 0 {
+		t.Errorf("%s failed: expected an empty slice, got %v", testName, actual)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testName := "TestMerge"
+	ctx := context.TODO()
+
+	merged := ToSlice(ctx, Merge(ctx, FromSlice(ctx, []int{1, 3, 5}), FromSlice(ctx, []int{2, 4}), FromSlice[int](ctx, nil)))
+	sort.Ints(merged)
+	if expected := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(expected, merged) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, merged)
+	}
+}
+
+func TestTeeAndBroadcast(t *testing.T) {
+	testName := "TestTeeAndBroadcast"
+	ctx := context.TODO()
+	input := []string{"a", "b", "c"}
+
+	left, right := Tee(ctx, FromSlice(ctx, input))
+	results := make(chan []string, 1)
+	go func() { results <- ToSlice(ctx, left) }()
+	if actual := ToSlice(ctx, right); !reflect.DeepEqual(input, actual) {
+		t.Errorf("%s failed: expected %v on the right branch, got %v", testName, input, actual)
+	}
+	if actual := <-results; !reflect.DeepEqual(input, actual) {
+		t.Errorf("%s failed: expected %v on the left branch, got %v", testName, input, actual)
+	}
+
+	outs := Broadcast(ctx, FromSlice(ctx, input), 3)
+	collected := Merge(ctx, outs...)
+	if actual := ToSlice(ctx, collected); len(actual) != 9 {
+		t.Errorf("%s failed: expected 9 values from 3 outputs, got %v", testName, actual)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	testName := "TestCancellation"
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	// the input is never closed: only the cancellation can terminate the pipelines
+	never := make(chan int)
+	outputs := []<-chan int{
+		OrDone(ctx, never),
+		Merge(ctx, never, never),
+		Throttle(ctx, never, time.Millisecond),
+		Debounce(ctx, never, time.Millisecond),
+		Buffer(ctx, never, 10, DropOldest),
+	}
+	left, right := Tee(ctx, never)
+	outputs = append(outputs, left, right)
+	batches := Batch(ctx, never, 10, time.Millisecond)
+	cancel()
+
+	for i, out := range outputs {
+		select {
+		case _, ok := <-out:
+			if ok {
+				t.Errorf("%s failed: output %d must not emit values", testName, i)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%s failed: output %d must be closed after the cancellation", testName, i)
+		}
+	}
+	select {
+	case <-batches:
+	case <-time.After(time.Second):
+		t.Errorf("%s failed: batches must be closed after the cancellation", testName)
+	}
+}
@@ -0,0 +1,137 @@
+package channels
+
+import (
+	"context"
+	"time"
+)
+
+// Batch groups the values of a channel in slices of at most size elements.
+// A batch is emitted as soon as it's full or when maxWait has elapsed since its first element was received;
+// the last partial batch is emitted when the input channel is closed. A size < 1 is treated as 1.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := make([]T, 0, size)
+		var timer *time.Timer
+		var deadline <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, deadline = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			ready := batch
+			batch = make([]T, 0, size)
+			return send(ctx, out, ready)
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case <-deadline:
+				timer, deadline = nil, nil
+				if !flush() {
+					return
+				}
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					deadline = timer.C
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle relays the values of a channel emitting at most one value per interval.
+// Values are never dropped: the reading from the input channel is slowed down instead.
+func Throttle[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var last time.Time
+		for {
+			v, ok := receive(ctx, in)
+			if !ok {
+				return
+			}
+			if wait := interval - time.Since(last); !last.IsZero() && wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+			if !send(ctx, out, v) {
+				return
+			}
+			last = time.Now()
+		}
+	}()
+	return out
+}
+
+// Debounce emits the latest value of a channel only after no other value has been received for the given wait.
+// When the input channel is closed, the pending value (if any) is emitted immediately.
+func Debounce[T any](ctx context.Context, in <-chan T, wait time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(wait)
+		timer.Stop()
+		defer timer.Stop()
+		var latest T
+		pending := false
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if pending {
+						send(ctx, out, latest)
+					}
+					return
+				}
+				latest, pending = v, true
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(wait)
+			case <-timer.C:
+				if pending {
+					pending = false
+					if !send(ctx, out, latest) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
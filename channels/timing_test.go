@@ -0,0 +1,114 @@
+package channels
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	testName := "TestBatch"
+	ctx := context.TODO()
+
+	batches := ToSlice(ctx, Batch(ctx, FromSlice(ctx, []int{1, 2, 3, 4, 5, 6, 7}), 3, time.Second))
+	if expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}; !reflect.DeepEqual(expected, batches) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, batches)
+	}
+
+	in := make(chan int)
+	out := Batch(ctx, in, 10, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	select {
+	case batch := <-out:
+		if !reflect.DeepEqual([]int{1, 2}, batch) {
+			t.Errorf("%s failed: expected the partial batch [1 2], got %v", testName, batch)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("%s failed: expected the partial batch to be emitted after maxWait", testName)
+	}
+	close(in)
+
+	for _, size := range []int{0, -1} {
+		batches := ToSlice(ctx, Batch(ctx, FromSlice(ctx, []int{1, 2}), size, time.Second))
+		if expected := [][]int{{1}, {2}}; !reflect.DeepEqual(expected, batches) {
+			t.Errorf("%s failed (size %d): expected %v, got %v", testName, size, expected, batches)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	testName := "TestThrottle"
+	ctx := context.TODO()
+
+	start := time.Now()
+	values := ToSlice(ctx, Throttle(ctx, FromSlice(ctx, []int{1, 2, 3, 4}), 20*time.Millisecond))
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("%s failed: expected at least 60ms for 4 values, took %s", testName, elapsed)
+	}
+	if expected := []int{1, 2, 3, 4}; !reflect.DeepEqual(expected, values) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, values)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	testName := "TestDebounce"
+	ctx := context.TODO()
+
+	in := make(chan string)
+	out := Debounce(ctx, in, 30*time.Millisecond)
+	go func() {
+		in <- "h"
+		in <- "he"
+		in <- "hello"
+		time.Sleep(100 * time.Millisecond)
+		in <- "hello world"
+		close(in)
+	}()
+	if expected, actual := []string{"hello", "hello world"}, ToSlice(ctx, out); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, actual)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	testName := "TestBuffer"
+	ctx := context.TODO()
+
+	testCases := []struct {
+		description string
+		policy      DropPolicy
+		expected    []int
+	}{
+		{description: "Block", policy: Block, expected: []int{1, 2, 3, 4, 5}},
+		{description: "DropNewest", policy: DropNewest, expected: []int{1, 2}},
+		{description: "DropOldest", policy: DropOldest, expected: []int{4, 5}},
+	}
+	for _, testCase := range testCases {
+		in := make(chan int)
+		out := Buffer(ctx, in, 2, testCase.policy)
+		go func() {
+			defer close(in)
+			for i := 1; i <= 5; i++ {
+				in <- i
+			}
+		}()
+		if testCase.policy != Block {
+			// let the producer overflow the buffer before consuming
+			time.Sleep(50 * time.Millisecond)
+		}
+		if actual := ToSlice(ctx, out); !reflect.DeepEqual(testCase.expected, actual) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+
+	// without a buffer the values are passed through
+	for _, size := range []int{0, -1} {
+		timeout, cancel := context.WithTimeout(ctx, time.Second)
+		values := ToSlice(timeout, Buffer(timeout, FromSlice(timeout, []int{1, 2, 3}), size, Block))
+		cancel()
+		if expected := []int{1, 2, 3}; !reflect.DeepEqual(expected, values) {
+			t.Errorf("%s failed (size %d): expected %v, got %v", testName, size, expected, values)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gyozatech/sushi/mapper"
+)
+
+// Option customizes the sources read by Load
+type Option func(*loader)
+
+type loader struct {
+	prefix   string
+	envFiles []string
+	// args are the command-line arguments to parse, nil if the flags aren't read
+	args      []string
+	lookupEnv func(key string) (string, bool)
+	readFile  func(path string) ([]byte, error)
+}
+
+// WithPrefix prepends a prefix to the names of all the environment variables, e.g. "APP" reads APP_DB_HOST
+func WithPrefix(prefix string) Option {
+	return func(l *loader) {
+		l.prefix = strings.TrimSuffix(prefix, "_") + "_"
+	}
+}
+
+// WithEnvFiles reads the given .env files, if they exist: the variables of the later files override the earlier ones
+func WithEnvFiles(paths ...string) Option {
+	return func(l *loader) {
+		l.envFiles = append(l.envFiles, paths...)
+	}
+}
+
+// WithFlags reads the fields with a `flag` tag from the command-line arguments os.Args[1:]:
+// any flag not declared by the struct is an error
+func WithFlags() Option {
+	return WithArgs(os.Args[1:])
+}
+
+// WithArgs reads the fields with a `flag` tag from the given command-line arguments
+func WithArgs(args []string) Option {
+	return func(l *loader) {
+		l.args = append([]string{}, args...)
+	}
+}
+
+// WithLookupEnv reads the environment variables through the given function instead of os.LookupEnv
+func WithLookupEnv(lookup func(key string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookupEnv = lookup
+	}
+}
+
+// field is a leaf field of the configuration struct
+type field struct {
+	path     string
+	env      string
+	flag     string
+	usage    string
+	def      string
+	hasDef   bool
+	required bool
+	secret   bool
+	value    reflect.Value
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Load fills the struct pointed by cfg reading, in order of increasing precedence:
+// the `default:"..."` tags, the .env files, the environment variables and the command-line flags.
+//
+// Every field is read from the environment variable named by its `env` tag or, by default, after its name in
+// upper snake case (nested structs prefix the names of their fields with their own name).
+// If the variable NAME_FILE is set instead of NAME, the value is read from the file it points to (Docker secrets).
+// Only the fields with a `flag` tag can be set from the command line, and only if WithFlags or WithArgs is given,
+// so that Load doesn't fail in the programs parsing their own flags.
+// The fields tagged with `required:"true"` must be set by one of the sources,
+// the ones tagged with `secret:"true"` are masked by Dump.
+func Load(cfg any, options ...Option) error {
+	l := &loader{
+		lookupEnv: os.LookupEnv,
+		readFile:  os.ReadFile,
+	}
+	for _, option := range options {
+		option(l)
+	}
+
+	fields, err := collect(cfg, l.prefix)
+	if err != nil {
+		return err
+	}
+
+	dotenv := map[string]string{}
+	for _, path := range l.envFiles {
+		vars, err := ReadEnvFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for k, v := range vars {
+			dotenv[k] = v
+		}
+	}
+
+	flags, err := l.parseFlags(fields)
+	if err != nil {
+		return err
+	}
+
+	missing := []string{}
+	for _, f := range fields {
+		raw, source, found, err := l.resolve(f, dotenv, flags)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if f.required {
+				missing = append(missing, f.env)
+			}
+			continue
+		}
+		if err := set(f.value, raw); err != nil {
+			return fmt.Errorf("config: invalid value for %s from %s: %w", f.env, source, err)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("config: missing required values: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// resolve finds the raw value of a field in the source with the highest precedence
+func (l *loader) resolve(f field, dotenv map[string]string, flags map[string]string) (raw string, source string, found bool, err error) {
+	if v, ok := flags[f.flag]; ok && f.flag != "" {
+		return v, "flag -" + f.flag, true, nil
+	}
+	lookups := []struct {
+		source string
+		lookup func(string) (string, bool)
+	}{
+		{source: "environment", lookup: l.lookupEnv},
+		{source: ".env file", lookup: func(key string) (string, bool) {
+			v, ok := dotenv[key]
+			return v, ok
+		}},
+	}
+	for _, lookup := range lookups {
+		if v, ok := lookup.lookup(f.env); ok {
+			return v, lookup.source, true, nil
+		}
+		if path, ok := lookup.lookup(f.env + "_FILE"); ok {
+			content, err := l.readFile(path)
+			if err != nil {
+				return "", "", false, fmt.Errorf("config: cannot read %s_FILE: %w", f.env, err)
+			}
+			return strings.TrimRight(string(content), "\r\n"), "file " + path, true, nil
+		}
+	}
+	if f.hasDef {
+		return f.def, "default", true, nil
+	}
+	return "", "", false, nil
+}
+
+func (l *loader) parseFlags(fields []field) (map[string]string, error) {
+	if l.args == nil {
+		return map[string]string{}, nil
+	}
+	set := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+		usage := f.usage
+		if usage == "" {
+			usage = "sets " + f.env
+		}
+		set.Var(&flagValue{value: f.def, boolean: f.value.Kind() == reflect.Bool}, f.flag, usage)
+	}
+	if err := set.Parse(l.args); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	result := map[string]string{}
+	set.Visit(func(fl *flag.Flag) {
+		result[fl.Name] = fl.Value.String()
+	})
+	return result, nil
+}
+
+// flagValue is the raw value of a flag: the flags of the bool fields can be set without a value, e.g. -debug
+type flagValue struct {
+	value   string
+	boolean bool
+}
+
+func (v *flagValue) String() string {
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.boolean
+}
+
+// set parses a raw value into a field: slices are read as comma-separated lists
+func set(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 {
+		items := []string{}
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		target := reflect.New(value.Type())
+		if err := mapper.Into(items, target.Interface()); err != nil {
+			return err
+		}
+		value.Set(target.Elem())
+		return nil
+	}
+	return mapper.Into(raw, value.Addr().Interface())
+}
+
+// collect lists the leaf fields of the configuration struct
+func collect(cfg any, prefix string) ([]field, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: expected a non-nil pointer to a struct, got %T", cfg)
+	}
+	fields := []field{}
+	walk(v.Elem(), "", prefix, &fields)
+	return fields, nil
+}
+
+func walk(v reflect.Value, path, envPrefix string, fields *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		env := sf.Tag.Get("env")
+		if env == "-" {
+			continue
+		}
+		if env == "" {
+			env = toSnakeCase(sf.Name)
+		}
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		fv := v.Field(i)
+
+		if isNested(sf.Type) {
+			nestedPrefix := envPrefix + env + "_"
+			if sf.Anonymous {
+				nestedPrefix = envPrefix
+			}
+			walk(fv, fieldPath, nestedPrefix, fields)
+			continue
+		}
+		def, hasDef := sf.Tag.Lookup("default")
+		*fields = append(*fields, field{
+			path:     fieldPath,
+			env:      envPrefix + env,
+			flag:     sf.Tag.Get("flag"),
+			usage:    sf.Tag.Get("usage"),
+			def:      def,
+			hasDef:   hasDef,
+			required: sf.Tag.Get("required") == "true",
+			secret:   sf.Tag.Get("secret") == "true",
+			value:    fv,
+		})
+	}
+}
+
+// isNested returns true for the struct fields whose own fields are configuration values
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// toSnakeCase converts a Go name into upper snake case, e.g. MaxIdleConns into MAX_IDLE_CONNS and DBHost into DB_HOST
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,262 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type database struct {
+	Host     string `default:"localhost"`
+	Port     int    `default:"5432" flag:"db-port"`
+	Password string `secret:"true"`
+}
+
+type serviceConfig struct {
+	Name      string        `required:"true"`
+	Debug     bool          `flag:"debug"`
+	Timeout   time.Duration `default:"5s"`
+	MaxBody   ByteSize      `default:"1MiB"`
+	Origins   []string      `env:"CORS_ORIGINS"`
+	Ports     []int
+	DB        database
+	Internal  string `env:"-"`
+	unexposed string
+}
+
+func env(vars map[string]string) Option {
+	return WithLookupEnv(func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dotenv := writeFile(t, ".env", "# local settings\nexport APP_NAME=from-dotenv\nAPP_DB_HOST='db.local'\nAPP_TIMEOUT=10s # overridden\n")
+	secret := writeFile(t, "password", "s3cr3t\n")
+
+	var cfg serviceConfig
+	err := Load(&cfg,
+		WithPrefix("APP"),
+		WithEnvFiles(dotenv, filepath.Join(t.TempDir(), "missing.env")),
+		WithArgs([]string{"-debug", "-db-port", "6432"}),
+		env(map[string]string{
+			"APP_NAME":             "orders",
+			"APP_CORS_ORIGINS":     "https://a.example, https://b.example",
+			"APP_PORTS":            "80,443",
+			"APP_DB_PORT":          "7000",
+			"APP_DB_PASSWORD_FILE": secret,
+			"APP_INTERNAL":         "ignored",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load failed: unexpected error %v", err)
+	}
+
+	expected := serviceConfig{
+		Name:    "orders",
+		Debug:   true,
+		Timeout: 10 * time.Second,
+		MaxBody: MiB,
+		Origins: []string{"https://a.example", "https://b.example"},
+		Ports:   []int{80, 443},
+		DB:      database{Host: "db.local", Port: 6432, Password: "s3cr3t"},
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("Load failed: expected %+v, got %+v", expected, cfg)
+	}
+}
+
+func TestNoFlagsByDefault(t *testing.T) {
+	// os.Args holds the flags of the test binary, e.g. -test.timeout, which the struct doesn't declare
+	var cfg serviceConfig
+	if err := Load(&cfg, env(map[string]string{"NAME": "x", "DB_PORT": "7000"})); err != nil || cfg.DB.Port != 7000 {
+		t.Errorf("%s failed: expected the flags to be ignored, got port %d (%v)", t.Name(), cfg.DB.Port, err)
+	}
+	if err := Load(&cfg, env(map[string]string{"NAME": "x"}), WithArgs(nil)); err != nil {
+		t.Errorf("%s failed: unexpected error %v with no arguments", t.Name(), err)
+	}
+}
+
+func TestBoolFlags(t *testing.T) {
+	tests := []struct {
+		testName string
+		args     []string
+		debug    bool
+		port     int
+	}{
+		{testName: "without value", args: []string{"-debug", "-db-port", "1"}, debug: true, port: 1},
+		{testName: "with value", args: []string{"-debug=false"}, debug: false, port: 5432},
+		{testName: "not set", args: []string{"-db-port=2"}, debug: true, port: 2},
+	}
+
+	for _, test := range tests {
+		var cfg serviceConfig
+		err := Load(&cfg, env(map[string]string{"NAME": "x", "DEBUG": "true"}), WithArgs(test.args))
+		if err != nil || cfg.Debug != test.debug || cfg.DB.Port != test.port {
+			t.Errorf("%s failed (%s): expected debug %t and port %d, got %t and %d (%v)", t.Name(), test.testName, test.debug, test.port, cfg.Debug, cfg.DB.Port, err)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      any
+		options  []Option
+		expected string
+	}{
+		{
+			testName: "missing required",
+			cfg:      &serviceConfig{},
+			options:  []Option{env(nil)},
+			expected: "config: missing required values: NAME",
+		},
+		{
+			testName: "invalid value",
+			cfg:      &serviceConfig{},
+			options:  []Option{env(map[string]string{"NAME": "x", "DB_PORT": "abc"})},
+			expected: "config: invalid value for DB_PORT from environment",
+		},
+		{
+			testName: "invalid flag value",
+			cfg:      &serviceConfig{},
+			options:  []Option{env(map[string]string{"NAME": "x"}), WithArgs([]string{"-db-port=abc"})},
+			expected: "config: invalid value for DB_PORT from flag -db-port",
+		},
+		{
+			testName: "unknown flag",
+			cfg:      &serviceConfig{},
+			options:  []Option{env(map[string]string{"NAME": "x"}), WithArgs([]string{"-verbose"})},
+			expected: "config: flag provided but not defined: -verbose",
+		},
+		{
+			testName: "missing secret file",
+			cfg:      &serviceConfig{},
+			options:  []Option{env(map[string]string{"NAME": "x", "DB_PASSWORD_FILE": "/does/not/exist"})},
+			expected: "config: cannot read DB_PASSWORD_FILE",
+		},
+		{
+			testName: "not a pointer",
+			cfg:      serviceConfig{},
+			expected: "config: expected a non-nil pointer to a struct",
+		},
+	}
+
+	for _, test := range tests {
+		err := Load(test.cfg, test.options...)
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("%s failed (%s): expected error %q, got %v", t.Name(), test.testName, test.expected, err)
+		}
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := writeFile(t, ".env", strings.Join([]string{
+		"# comment",
+		"",
+		"PLAIN=value",
+		"SPACED = spaced value # comment",
+		`DOUBLE="line\nbreak \"quoted\""`,
+		"SINGLE='raw\\n # not a comment'",
+		"EMPTY=",
+		"export EXPORTED=yes",
+	}, "\n"))
+
+	vars, err := ReadEnvFile(path)
+	if err != nil {
+		t.Fatalf("ReadEnvFile failed: unexpected error %v", err)
+	}
+	expected := map[string]string{
+		"PLAIN":    "value",
+		"SPACED":   "spaced value",
+		"DOUBLE":   "line\nbreak \"quoted\"",
+		"SINGLE":   `raw\n # not a comment`,
+		"EMPTY":    "",
+		"EXPORTED": "yes",
+	}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Errorf("ReadEnvFile failed: expected %q, got %q", expected, vars)
+	}
+
+	if _, err := ReadEnvFile(writeFile(t, "bad.env", "OK=1\nNOT VALID\n")); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("ReadEnvFile failed: expected an error on line 2, got %v", err)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+		fails    bool
+	}{
+		{input: "512", expected: 512},
+		{input: "512B", expected: 512},
+		{input: "10KB", expected: 10 * KB},
+		{input: "10kb", expected: 10 * KB},
+		{input: "1.5GiB", expected: GiB + 512*MiB},
+		{input: "2 MiB", expected: 2 * MiB},
+		{input: "1TB", expected: TB},
+		{input: "", fails: true},
+		{input: "MB", fails: true},
+		{input: "10XB", fails: true},
+		{input: "1.2.3KB", fails: true},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseByteSize(test.input)
+		if test.fails != (err != nil) || actual != test.expected {
+			t.Errorf("%s failed (%q): expected %d (fails: %t), got %d (%v)", t.Name(), test.input, test.expected, test.fails, actual, err)
+		}
+	}
+
+	for size, expected := range map[ByteSize]string{0: "0B", 100: "100B", 2 * KiB: "2KiB", 3 * GiB: "3GiB", KB: "1000B"} {
+		if size.String() != expected {
+			t.Errorf("%s failed (String): expected %s, got %s", t.Name(), expected, size.String())
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Name":         "NAME",
+		"MaxIdleConns": "MAX_IDLE_CONNS",
+		"DBHost":       "DB_HOST",
+		"HTTPServer2":  "HTTP_SERVER2",
+		"APIKey":       "API_KEY",
+	} {
+		if actual := toSnakeCase(input); actual != expected {
+			t.Errorf("%s failed (%s): expected %s, got %s", t.Name(), input, expected, actual)
+		}
+	}
+}
+
+func TestDump(t *testing.T) {
+	cfg := serviceConfig{
+		Name:    "orders",
+		Timeout: time.Second,
+		MaxBody: 2 * MiB,
+		DB:      database{Host: "db", Port: 5432, Password: "s3cr3t"},
+	}
+
+	dump := Dump(&cfg, WithPrefix("APP"))
+	for _, line := range []string{"APP_NAME=orders", "APP_TIMEOUT=1s", "APP_MAX_BODY=2MiB", "APP_DB_HOST=db", "APP_DB_PASSWORD=******"} {
+		if !strings.Contains(dump, line+"\n") {
+			t.Errorf("%s failed: expected line %s in\n%s", t.Name(), line, dump)
+		}
+	}
+	if strings.Contains(dump, "s3cr3t") || strings.Contains(dump, "INTERNAL") {
+		t.Errorf("%s failed: unexpected secret or excluded field in\n%s", t.Name(), dump)
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const mask = "******"
+
+// Dump returns a printable representation of the configuration, one NAME=value line per field,
+// masking the values of the fields tagged with `secret:"true"`
+func Dump(cfg any, options ...Option) string {
+	l := &loader{}
+	for _, option := range options {
+		option(l)
+	}
+	fields, err := collect(cfg, l.prefix)
+	if err != nil {
+		return err.Error()
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		value := fmt.Sprintf("%v", f.value.Interface())
+		if f.secret && !f.value.IsZero() {
+			value = mask
+		}
+		fmt.Fprintf(&b, "%s=%s\n", f.env, value)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes which can be parsed from strings like "512", "10KB", "1.5GiB"
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+	KB            = 1000 * Byte
+	MB            = 1000 * KB
+	GB            = 1000 * MB
+	TB            = 1000 * GB
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+)
+
+var sizeUnits = map[string]ByteSize{
+	"": Byte, "b": Byte,
+	"k": KB, "kb": KB, "m": MB, "mb": MB, "g": GB, "gb": GB, "t": TB, "tb": TB,
+	"ki": KiB, "kib": KiB, "mi": MiB, "mib": MiB, "gi": GiB, "gib": GiB, "ti": TiB, "tib": TiB,
+}
+
+// ParseByteSize parses a size made of a number and an optional unit (B, KB, MB, GB, TB or KiB, MiB, GiB, TiB), case-insensitive
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	multiplier, ok := sizeUnits[unit]
+	if !ok || number == "" {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	size := value * float64(multiplier)
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size %q out of range", s)
+	}
+	return ByteSize(size), nil
+}
+
+// UnmarshalText parses the size from its textual representation
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+// String formats the size with the biggest binary unit which represents it exactly
+func (s ByteSize) String() string {
+	for _, unit := range []struct {
+		name string
+		size ByteSize
+	}{{"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}} {
+		if s != 0 && s%unit.size == 0 {
+			return fmt.Sprintf("%d%s", s/unit.size, unit.name)
+		}
+	}
+	return fmt.Sprintf("%dB", int64(s))
+}
@@ -0,0 +1,47 @@
+package fake
+
+var firstNames = []string{
+	"Alice", "Andrea", "Anna", "Benjamin", "Bruno", "Carla", "Charlotte", "Daniel", "Davide", "Elena",
+	"Emma", "Federico", "Francesca", "Gabriel", "Giulia", "Hannah", "Hugo", "Isabella", "Jack", "James",
+	"Julia", "Kenji", "Laura", "Leo", "Lucas", "Luca", "Marco", "Maria", "Matteo", "Mia",
+	"Noah", "Olivia", "Oscar", "Paolo", "Sakura", "Sara", "Sofia", "Thomas", "Valentina", "Yuki",
+}
+
+var lastNames = []string{
+	"Anderson", "Bianchi", "Brown", "Colombo", "Costa", "Davis", "Esposito", "Ferrari", "Fischer", "Garcia",
+	"Greco", "Johnson", "Jones", "Kato", "Lee", "Lopez", "Martin", "Martinez", "Miller", "Moore",
+	"Muller", "Nakamura", "Ricci", "Romano", "Rossi", "Russo", "Schmidt", "Smith", "Suzuki", "Tanaka",
+	"Taylor", "Thomas", "Wagner", "Walker", "Watanabe", "White", "Williams", "Wilson", "Yamamoto", "Young",
+}
+
+var streetNames = []string{
+	"Acacia", "Birch", "Cedar", "Chestnut", "Church", "Elm", "Garden", "Hill", "Lake", "Maple",
+	"Meadow", "Mill", "Oak", "Park", "Pine", "River", "Spruce", "Sunset", "Walnut", "Willow",
+}
+
+var streetSuffixes = []string{"Street", "Avenue", "Road", "Lane", "Boulevard", "Drive", "Court", "Way"}
+
+var cities = []string{
+	"Amsterdam", "Barcelona", "Berlin", "Bologna", "Boston", "Chicago", "Dublin", "Florence", "Kyoto", "Lisbon",
+	"London", "Lyon", "Madrid", "Milan", "Munich", "Naples", "Osaka", "Paris", "Rome", "Seattle",
+	"Sydney", "Tokyo", "Toronto", "Turin", "Vienna",
+}
+
+var countries = []string{
+	"Australia", "Austria", "Canada", "France", "Germany", "Ireland", "Italy", "Japan", "Netherlands", "Portugal",
+	"Spain", "Sweden", "Switzerland", "United Kingdom", "United States",
+}
+
+// emailDomains are reserved for documentation (RFC 2606): the fake addresses never reach anyone
+var emailDomains = []string{"example.com", "example.net", "example.org"}
+
+var topLevelDomains = []string{"com", "net", "org", "io", "dev"}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do",
+	"eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim",
+	"ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip",
+	"ex", "ea", "commodo", "consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat",
+	"non", "proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum",
+}
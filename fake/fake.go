@@ -0,0 +1,212 @@
+package fake
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// Faker generates fake data for test fixtures. Fakers created with the same seed generate the same data
+// when called in the same order: it is NOT suitable for secrets, use utils.RandomString for them.
+type Faker struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New returns a Faker whose data is determined by the seed
+func New(seed int64) *Faker {
+	return &Faker{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Address is a fake postal address
+type Address struct {
+	Street     string
+	City       string
+	PostalCode string
+	Country    string
+}
+
+// String returns the address on a single line
+func (a Address) String() string {
+	return fmt.Sprintf("%s, %s %s, %s", a.Street, a.PostalCode, a.City, a.Country)
+}
+
+// Int returns a number in [min, max], which can span the whole int range
+func (f *Faker) Int(min, max int) int {
+	if max <= min {
+		return min
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// the width is computed as unsigned, since max-min overflows for the ranges wider than MaxInt
+	width := uint64(max) - uint64(min)
+	if width < math.MaxInt64 {
+		return min + int(f.rand.Int63n(int64(width)+1))
+	}
+	for {
+		if n := f.rand.Uint64(); n <= width {
+			return int(uint64(min) + n)
+		}
+	}
+}
+
+// Float64 returns a number in [min, max)
+func (f *Faker) Float64(min, max float64) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return min + f.rand.Float64()*(max-min)
+}
+
+// Bool returns true or false
+func (f *Faker) Bool() bool {
+	return f.Int(0, 1) == 1
+}
+
+// StringFrom returns a string of the given length picking its characters from the charset,
+// like utils.RandomStringFrom. The charset must not be empty, unless the length is 0.
+func (f *Faker) StringFrom(charset string, length int) string {
+	characters := []rune(charset)
+	if len(characters) == 0 && length > 0 {
+		panic("fake: StringFrom requires a non-empty charset")
+	}
+	str := make([]rune, length)
+	for i := range str {
+		str[i] = characters[f.Int(0, len(characters)-1)]
+	}
+	return string(str)
+}
+
+// String returns an alphanumeric string of the given length
+func (f *Faker) String(length int) string {
+	return f.StringFrom(utils.LowerLetters+utils.UpperLetters+utils.Digits, length)
+}
+
+// FirstName returns a first name
+func (f *Faker) FirstName() string {
+	return f.pick(firstNames)
+}
+
+// LastName returns a last name
+func (f *Faker) LastName() string {
+	return f.pick(lastNames)
+}
+
+// Name returns a first name followed by a last name
+func (f *Faker) Name() string {
+	return f.FirstName() + " " + f.LastName()
+}
+
+// Username returns a lowercase username like emma.rossi42
+func (f *Faker) Username() string {
+	return fmt.Sprintf("%s.%s%d", strings.ToLower(f.FirstName()), strings.ToLower(f.LastName()), f.Int(1, 99))
+}
+
+// Email returns an email address of a domain reserved for documentation, like emma.rossi42@example.com
+func (f *Faker) Email() string {
+	return f.Username() + "@" + f.pick(emailDomains)
+}
+
+// Phone returns a phone number of the range reserved for fictional use, like +1 415-555-0142
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("+1 %03d-555-01%02d", f.Int(201, 989), f.Int(0, 99))
+}
+
+// Street returns a street address, like 42 Maple Avenue
+func (f *Faker) Street() string {
+	return fmt.Sprintf("%d %s %s", f.Int(1, 999), f.pick(streetNames), f.pick(streetSuffixes))
+}
+
+// City returns the name of a city
+func (f *Faker) City() string {
+	return f.pick(cities)
+}
+
+// Country returns the name of a country
+func (f *Faker) Country() string {
+	return f.pick(countries)
+}
+
+// PostalCode returns a 5-digit postal code
+func (f *Faker) PostalCode() string {
+	return f.StringFrom(utils.Digits, 5)
+}
+
+// Address returns a postal address
+func (f *Faker) Address() Address {
+	return Address{Street: f.Street(), City: f.City(), PostalCode: f.PostalCode(), Country: f.Country()}
+}
+
+// IPv4 returns a unicast IPv4 address
+func (f *Faker) IPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", f.Int(1, 223), f.Int(0, 255), f.Int(0, 255), f.Int(1, 254))
+}
+
+// IPv6 returns an IPv6 address in its full form, without "::" compression
+func (f *Faker) IPv6() string {
+	groups := make([]string, 8)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("%x", f.Int(0, 0xffff))
+	}
+	return strings.Join(groups, ":")
+}
+
+// Domain returns a domain name, like rossi-lorem.io
+func (f *Faker) Domain() string {
+	return strings.ToLower(f.LastName()) + "-" + f.Word() + "." + f.pick(topLevelDomains)
+}
+
+// URL returns an https URL, like https://rossi-lorem.io/dolor
+func (f *Faker) URL() string {
+	return "https://" + f.Domain() + "/" + f.Word()
+}
+
+// Word returns a lorem ipsum word
+func (f *Faker) Word() string {
+	return f.pick(loremWords)
+}
+
+// Words returns n lorem ipsum words
+func (f *Faker) Words(n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.Word()
+	}
+	return words
+}
+
+// Sentence returns a capitalized sentence of n lorem ipsum words, ending with a period
+func (f *Faker) Sentence(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	sentence := strings.Join(f.Words(n), " ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// Paragraph returns n sentences of 4 to 12 words
+func (f *Faker) Paragraph(n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = f.Sentence(f.Int(4, 12))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// Date returns a time in [from, to), in the location of from
+func (f *Faker) Date(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return from.Add(time.Duration(f.rand.Int63n(int64(to.Sub(from)))))
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.Int(0, len(values)-1)]
+}
@@ -0,0 +1,175 @@
+package fake
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+func TestSeedReproducibility(t *testing.T) {
+	generate := func(f *Faker) []string {
+		return []string{f.Name(), f.Email(), f.Address().String(), f.IPv4(), f.IPv6(), f.URL(), f.Paragraph(2), f.String(12)}
+	}
+	first, second, other := generate(New(42)), generate(New(42)), generate(New(7))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("%s failed: expected the same data with the same seed, got %v and %v", t.Name(), first, second)
+	}
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("%s failed: expected different data with different seeds, got %v", t.Name(), first)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	f := New(1)
+	for i := 0; i < 200; i++ {
+		if ip := f.IPv4(); !utils.ValidateIPv4(ip, false) || net.ParseIP(ip) == nil {
+			t.Fatalf("%s failed: invalid IPv4 %s", t.Name(), ip)
+		}
+		if ip := f.IPv6(); !utils.ValidateIPv6(ip, false) || net.ParseIP(ip) == nil {
+			t.Fatalf("%s failed: invalid IPv6 %s", t.Name(), ip)
+		}
+		if email := f.Email(); !strings.HasSuffix(email, ".com") && !strings.HasSuffix(email, ".net") && !strings.HasSuffix(email, ".org") {
+			t.Fatalf("%s failed: unexpected email domain %s", t.Name(), email)
+		} else if _, err := mail.ParseAddress(email); err != nil {
+			t.Fatalf("%s failed: invalid email %s: %v", t.Name(), email, err)
+		}
+		if u, err := url.Parse(f.URL()); err != nil || u.Scheme != "https" || u.Host == "" {
+			t.Fatalf("%s failed: invalid URL %v: %v", t.Name(), u, err)
+		}
+		if n := f.Int(-3, 3); n < -3 || n > 3 {
+			t.Fatalf("%s failed: %d out of [-3, 3]", t.Name(), n)
+		}
+		if n := f.Int(math.MaxInt-1, math.MaxInt); n < math.MaxInt-1 {
+			t.Fatalf("%s failed: %d out of [MaxInt-1, MaxInt]", t.Name(), n)
+		}
+		if n := f.Int(-1, math.MaxInt); n < -1 {
+			t.Fatalf("%s failed: %d out of [-1, MaxInt]", t.Name(), n)
+		}
+		f.Int(math.MinInt, math.MaxInt) // the full range doesn't overflow
+	}
+}
+
+func TestLorem(t *testing.T) {
+	f := New(1)
+	sentence := f.Sentence(5)
+	if len(strings.Fields(sentence)) != 5 || !strings.HasSuffix(sentence, ".") || strings.ToUpper(sentence[:1]) != sentence[:1] {
+		t.Errorf("%s failed: unexpected sentence %q", t.Name(), sentence)
+	}
+	if f.Sentence(0) != "" {
+		t.Errorf("%s failed: expected an empty sentence of 0 words", t.Name())
+	}
+	if paragraph := f.Paragraph(3); strings.Count(paragraph, ".") != 3 {
+		t.Errorf("%s failed: expected 3 sentences, got %q", t.Name(), paragraph)
+	}
+}
+
+func TestDate(t *testing.T) {
+	f := New(1)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	for i := 0; i < 100; i++ {
+		if d := f.Date(from, to); d.Before(from) || !d.Before(to) {
+			t.Fatalf("%s failed: %s out of [%s, %s)", t.Name(), d, from, to)
+		}
+	}
+	if d := f.Date(to, from); !d.Equal(to) {
+		t.Errorf("%s failed: expected %s for an empty range, got %s", t.Name(), to, d)
+	}
+}
+
+type fixture struct {
+	ID        int64
+	Name      string   `fake:"name"`
+	Email     string   `fake:"email"`
+	Aliases   []string `fake:"username"`
+	Homepage  *string  `fake:"url"`
+	IP        string   `fake:"ipv4"`
+	Score     float64
+	Active    bool
+	CreatedAt time.Time
+	Tags      map[string]int
+	Address   struct {
+		City    string `fake:"city"`
+		Country string `fake:"country"`
+	}
+	Ignored string `fake:"-"`
+	secret  string
+}
+
+func TestFill(t *testing.T) {
+	var first, second fixture
+	if err := New(42).Fill(&first); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if err := New(42).Fill(&second); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("%s failed: expected the same fixture with the same seed, got %+v and %+v", t.Name(), first, second)
+	}
+
+	if !strings.Contains(first.Name, " ") || !strings.Contains(first.Email, "@") || !utils.ValidateIPv4(first.IP, false) {
+		t.Errorf("%s failed: tags not applied to %+v", t.Name(), first)
+	}
+	if len(first.Aliases) == 0 || !strings.Contains(first.Aliases[0], ".") || first.Homepage == nil || !strings.HasPrefix(*first.Homepage, "https://") {
+		t.Errorf("%s failed: tags not applied to slices and pointers: %v %v", t.Name(), first.Aliases, first.Homepage)
+	}
+	if first.CreatedAt.Year() < 2000 || first.CreatedAt.Year() >= 2030 || len(first.Tags) == 0 || first.Address.City == "" || first.Address.Country == "" {
+		t.Errorf("%s failed: unexpected fixture %+v", t.Name(), first)
+	}
+	if first.Ignored != "" || first.secret != "" {
+		t.Errorf("%s failed: expected ignored and unexported fields untouched, got %+v", t.Name(), first)
+	}
+}
+
+func TestFillErrors(t *testing.T) {
+	var target fixture
+	tests := []struct {
+		testName string
+		target   any
+		expected error
+	}{
+		{"not a pointer", target, ErrInvalidTarget},
+		{"nil pointer", (*fixture)(nil), ErrInvalidTarget},
+		{"unknown tag", &struct {
+			Name string `fake:"nickname"`
+		}{}, ErrUnknownTag},
+		{"tag on a number", &struct {
+			Age int `fake:"email"`
+		}{}, ErrUnknownTag},
+	}
+	for _, test := range tests {
+		if err := New(1).Fill(test.target); !errors.Is(err, test.expected) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), test.testName, test.expected, err)
+		}
+	}
+}
+
+type node struct {
+	Name     string
+	Next     *node
+	Children []node
+}
+
+func TestFillRecursiveTypes(t *testing.T) {
+	var list node
+	if err := New(7).Fill(&list); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if list.Name == "" || list.Next != nil {
+		t.Errorf("%s failed: expected a filled head without a next node, got %+v", t.Name(), list)
+	}
+	for _, child := range list.Children {
+		if child.Name != "" {
+			t.Errorf("%s failed: expected the nested nodes left empty, got %+v", t.Name(), child)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var (
+	// ErrInvalidTarget is returned by Fill when it's not given a non-nil pointer
+	ErrInvalidTarget = errors.New("fake: the target must be a non-nil pointer")
+	// ErrUnknownTag is returned by Fill for the fake tags it doesn't know or cannot apply to the type of the field
+	ErrUnknownTag = errors.New("fake: unknown tag")
+)
+
+// generators are the string generators which can be selected with the fake tag
+var generators = map[string]func(*Faker) string{
+	"first_name":  (*Faker).FirstName,
+	"last_name":   (*Faker).LastName,
+	"name":        (*Faker).Name,
+	"username":    (*Faker).Username,
+	"email":       (*Faker).Email,
+	"phone":       (*Faker).Phone,
+	"street":      (*Faker).Street,
+	"city":        (*Faker).City,
+	"country":     (*Faker).Country,
+	"postal_code": (*Faker).PostalCode,
+	"address":     func(f *Faker) string { return f.Address().String() },
+	"ipv4":        (*Faker).IPv4,
+	"ipv6":        (*Faker).IPv6,
+	"domain":      (*Faker).Domain,
+	"url":         (*Faker).URL,
+	"word":        (*Faker).Word,
+	"sentence":    func(f *Faker) string { return f.Sentence(f.Int(4, 12)) },
+	"paragraph":   func(f *Faker) string { return f.Paragraph(f.Int(2, 5)) },
+}
+
+// the range of the dates generated by Fill: it's fixed to keep the data reproducible
+var (
+	fillFrom = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	fillTo   = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Fill populates the value pointed by target according to its type: strings get a lorem word, numbers a value
+// between 0 and 100, time.Time a date between 2000 and 2030, slices and maps 1 to 3 elements, nil pointers a new value
+// and struct fields are filled recursively. The pointers to a struct type already being filled are left nil, so that
+// recursive types such as linked lists and trees terminate. The `fake` tag of a string field (or of a slice, array or pointer of strings)
+// selects a generator, e.g. `fake:"email"`, and `fake:"-"` leaves the field untouched.
+func (f *Faker) Fill(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return ErrInvalidTarget
+	}
+	return f.fill(v.Elem(), "", map[reflect.Type]bool{})
+}
+
+// fill populates v, path holding the struct types being filled
+func (f *Faker) fill(v reflect.Value, tag string, path map[reflect.Type]bool) error {
+	if tag != "" && v.Kind() != reflect.String && v.Kind() != reflect.Slice && v.Kind() != reflect.Array && v.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w %q for %s", ErrUnknownTag, tag, v.Type())
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if tag == "" {
+			v.SetString(f.Word())
+			return nil
+		}
+		generate, ok := generators[tag]
+		if !ok {
+			return fmt.Errorf("%w %q", ErrUnknownTag, tag)
+		}
+		v.SetString(generate(f))
+	case reflect.Bool:
+		v.SetBool(f.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(f.Int(0, 100)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(uint64(f.Int(0, 100)))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(f.Float64(0, 100))
+	case reflect.Pointer:
+		if v.IsNil() {
+			if path[v.Type().Elem()] {
+				return nil
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return f.fill(v.Elem(), tag, path)
+	case reflect.Slice:
+		n := f.Int(1, 3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := f.fill(v.Index(i), tag, path); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for i := f.Int(1, 3); i > 0; i-- {
+			key, value := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			if err := f.fill(key, "", path); err != nil {
+				return err
+			}
+			if err := f.fill(value, "", path); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, value)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(f.Date(fillFrom, fillTo)))
+			return nil
+		}
+		if path[v.Type()] {
+			return nil
+		}
+		path[v.Type()] = true
+		defer delete(path, v.Type())
+		return f.fillStruct(v, path)
+	}
+	return nil
+}
+
+func (f *Faker) fillStruct(v reflect.Value, path map[reflect.Type]bool) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("fake")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		if err := f.fill(v.Field(i), tag, path); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrIllegalTransition is returned (wrapped) when an event is fired which is not permitted from the current state
+var ErrIllegalTransition = errors.New("illegal transition")
+
+// ErrGuardRejected is returned (wrapped) when the event is permitted but every guard of the transition rejected it
+var ErrGuardRejected = errors.New("transition rejected by guard")
+
+// ErrReentrantFire is returned (wrapped) when an action or a guard fires an event on its own machine
+// with the context it received: the event must be fired after the transition in progress completes
+var ErrReentrantFire = errors.New("event fired during a transition")
+
+// Transition describes the move from a state to another state triggered by an event
+type Transition[S comparable, E comparable] struct {
+	From  S
+	Event E
+	To    S
+}
+
+// Record is an entry of the transition history of a StateMachine
+type Record[S comparable, E comparable] struct {
+	Transition[S, E]
+	At time.Time
+}
+
+// Guard is a condition which must hold for a transition to be performed
+type Guard[S comparable, E comparable] func(ctx context.Context, t Transition[S, E]) bool
+
+// Action is a side effect performed when entering or exiting a state:
+// returning an error aborts the transition, see StateMachine.Fire
+type Action[S comparable, E comparable] func(ctx context.Context, t Transition[S, E]) error
+
+// TransitionError is the error returned when a fired event cannot be applied to the current state
+type TransitionError[S comparable, E comparable] struct {
+	State S
+	Event E
+	Err   error
+}
+
+func (e *TransitionError[S, E]) Error() string {
+	return fmt.Sprintf("event %v from state %v: %s", e.Event, e.State, e.Err)
+}
+
+func (e *TransitionError[S, E]) Unwrap() error {
+	return e.Err
+}
+
+type transition[S comparable, E comparable] struct {
+	to     S
+	guards []Guard[S, E]
+}
+
+type edge[S comparable, E comparable] struct {
+	from    S
+	event   E
+	to      S
+	guarded bool
+}
+
+// StateMachine is a generic finite-state machine with declared transitions, guards and entry/exit actions.
+// It is safe for concurrent use: events are applied one at a time.
+type StateMachine[S comparable, E comparable] struct {
+	// firing serializes the transitions, while mu guards the fields and is never held while running the guards
+	// and the actions, so that they can read the machine
+	firing      sync.Mutex
+	mu          sync.Mutex
+	initial     S
+	current     S
+	transitions map[S]map[E][]transition[S, E]
+	onEntry     map[S][]Action[S, E]
+	onExit      map[S][]Action[S, E]
+	edges       []edge[S, E]
+	history     []Record[S, E]
+	now         func() time.Time
+}
+
+// New creates a new StateMachine starting in the initial state
+func New[S comparable, E comparable](initial S) *StateMachine[S, E] {
+	return &StateMachine[S, E]{
+		initial:     initial,
+		current:     initial,
+		transitions: map[S]map[E][]transition[S, E]{},
+		onEntry:     map[S][]Action[S, E]{},
+		onExit:      map[S][]Action[S, E]{},
+		now:         time.Now,
+	}
+}
+
+// Permit declares that the event moves the machine from a state to another one, provided that all the guards hold.
+// The same event can be permitted several times from the same state with different guards:
+// the first declared transition whose guards all hold is the one performed.
+func (sm *StateMachine[S, E]) Permit(from S, event E, to S, guards ...Guard[S, E]) *StateMachine[S, E] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	events, ok := sm.transitions[from]
+	if !ok {
+		events = map[E][]transition[S, E]{}
+		sm.transitions[from] = events
+	}
+	events[event] = append(events[event], transition[S, E]{to: to, guards: guards})
+	sm.edges = append(sm.edges, edge[S, E]{from: from, event: event, to: to, guarded: len(guards) > 0})
+	return sm
+}
+
+// OnEntry registers an action performed every time the machine enters the given state
+func (sm *StateMachine[S, E]) OnEntry(state S, action Action[S, E]) *StateMachine[S, E] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onEntry[state] = append(sm.onEntry[state], action)
+	return sm
+}
+
+// OnExit registers an action performed every time the machine leaves the given state
+func (sm *StateMachine[S, E]) OnExit(state S, action Action[S, E]) *StateMachine[S, E] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onExit[state] = append(sm.onExit[state], action)
+	return sm
+}
+
+// Current returns the state the machine is in
+func (sm *StateMachine[S, E]) Current() S {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.current
+}
+
+// Can returns true if the event is permitted from the current state and at least one of its transitions passes the guards
+func (sm *StateMachine[S, E]) Can(ctx context.Context, event E) bool {
+	sm.mu.Lock()
+	from, candidates := sm.current, sm.transitions[sm.current][event]
+	sm.mu.Unlock()
+	_, err := resolve(ctx, from, event, candidates)
+	return err == nil
+}
+
+// firingKey is the context key marking the contexts passed to the guards and the actions by Fire
+type firingKey struct{}
+
+// Fire applies the event to the current state.
+// The exit actions of the current state are performed first, then the entry actions of the target state.
+// The guards and the actions can read the machine, which is still in the current state while they run,
+// but firing an event with the context they receive fails with ErrReentrantFire.
+//
+// If an action fails, the machine stays in the current state and the error is returned: the exit actions already
+// performed are not undone, so they must tolerate being performed again when the event is fired again.
+func (sm *StateMachine[S, E]) Fire(ctx context.Context, event E) error {
+	if ctx.Value(firingKey{}) == sm {
+		return &TransitionError[S, E]{State: sm.Current(), Event: event, Err: ErrReentrantFire}
+	}
+	sm.firing.Lock()
+	defer sm.firing.Unlock()
+	ctx = context.WithValue(ctx, firingKey{}, sm)
+
+	// the current state can only change while firing is held
+	sm.mu.Lock()
+	from, candidates := sm.current, sm.transitions[sm.current][event]
+	sm.mu.Unlock()
+	t, err := resolve(ctx, from, event, candidates)
+	if err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	actions := append(append([]Action[S, E]{}, sm.onExit[t.From]...), sm.onEntry[t.To]...)
+	sm.mu.Unlock()
+	for _, action := range actions {
+		if err := action(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.current = t.To
+	sm.history = append(sm.history, Record[S, E]{Transition: t, At: sm.now()})
+	return nil
+}
+
+// resolve finds the transition to perform for the event among the ones permitted from the state
+func resolve[S comparable, E comparable](ctx context.Context, from S, event E, candidates []transition[S, E]) (Transition[S, E], error) {
+	if len(candidates) == 0 {
+		return Transition[S, E]{}, &TransitionError[S, E]{State: from, Event: event, Err: ErrIllegalTransition}
+	}
+	for _, candidate := range candidates {
+		t := Transition[S, E]{From: from, Event: event, To: candidate.to}
+		if allowed(ctx, t, candidate.guards) {
+			return t, nil
+		}
+	}
+	return Transition[S, E]{}, &TransitionError[S, E]{State: from, Event: event, Err: ErrGuardRejected}
+}
+
+func allowed[S comparable, E comparable](ctx context.Context, t Transition[S, E], guards []Guard[S, E]) bool {
+	for _, guard := range guards {
+		if !guard(ctx, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// History returns a copy of the transitions performed so far, oldest first
+func (sm *StateMachine[S, E]) History() []Record[S, E] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	history := make([]Record[S, E], len(sm.history))
+	copy(history, sm.history)
+	return history
+}
+
+// Transitions returns the declared transitions in declaration order
+func (sm *StateMachine[S, E]) Transitions() []Transition[S, E] {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	transitions := make([]Transition[S, E], 0, len(sm.edges))
+	for _, e := range sm.edges {
+		transitions = append(transitions, Transition[S, E]{From: e.from, Event: e.event, To: e.to})
+	}
+	return transitions
+}
@@ -0,0 +1,202 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type orderState string
+type orderEvent string
+
+const (
+	created   orderState = "created"
+	paid      orderState = "paid"
+	shipped   orderState = "shipped"
+	cancelled orderState = "cancelled"
+
+	pay    orderEvent = "pay"
+	ship   orderEvent = "ship"
+	cancel orderEvent = "cancel"
+)
+
+func newOrderMachine(stock *int) *StateMachine[orderState, orderEvent] {
+	inStock := func(ctx context.Context, t Transition[orderState, orderEvent]) bool {
+		return *stock > 0
+	}
+	return New[orderState, orderEvent](created).
+		Permit(created, pay, paid).
+		Permit(created, cancel, cancelled).
+		Permit(paid, ship, shipped, inStock).
+		Permit(paid, cancel, cancelled)
+}
+
+func TestStateMachineFire(t *testing.T) {
+	testName := "TestStateMachineFire"
+	stock := 1
+	ctx := context.TODO()
+
+	sm := newOrderMachine(&stock)
+	if err := sm.Fire(ctx, pay); err != nil {
+		t.Errorf("%s failed: unexpected error %s", testName, err)
+	}
+	if err := sm.Fire(ctx, ship); err != nil {
+		t.Errorf("%s failed: unexpected error %s", testName, err)
+	}
+	if sm.Current() != shipped {
+		t.Errorf("%s failed: expected state %s, got %s", testName, shipped, sm.Current())
+	}
+
+	expected := []Transition[orderState, orderEvent]{
+		{From: created, Event: pay, To: paid},
+		{From: paid, Event: ship, To: shipped},
+	}
+	history := sm.History()
+	if len(history) != len(expected) {
+		t.Fatalf("%s failed: expected %d records, got %d", testName, len(expected), len(history))
+	}
+	for i, record := range history {
+		if record.Transition != expected[i] || record.At.IsZero() {
+			t.Errorf("%s failed: expected record %+v, got %+v", testName, expected[i], record)
+		}
+	}
+}
+
+func TestStateMachineRejections(t *testing.T) {
+	testName := "TestStateMachineRejections"
+	stock := 0
+	ctx := context.TODO()
+
+	sm := newOrderMachine(&stock)
+	err := sm.Fire(ctx, ship)
+	if !errors.Is(err, ErrIllegalTransition) {
+		t.Errorf("%s failed: expected ErrIllegalTransition, got %v", testName, err)
+	}
+	var transitionErr *TransitionError[orderState, orderEvent]
+	if !errors.As(err, &transitionErr) || transitionErr.State != created || transitionErr.Event != ship {
+		t.Errorf("%s failed: expected a TransitionError for %s from %s, got %v", testName, ship, created, err)
+	}
+
+	_ = sm.Fire(ctx, pay)
+	if sm.Can(ctx, ship) {
+		t.Errorf("%s failed: ship must not be allowed when out of stock", testName)
+	}
+	if err := sm.Fire(ctx, ship); !errors.Is(err, ErrGuardRejected) {
+		t.Errorf("%s failed: expected ErrGuardRejected, got %v", testName, err)
+	}
+	if sm.Current() != paid {
+		t.Errorf("%s failed: expected state %s, got %s", testName, paid, sm.Current())
+	}
+}
+
+func TestStateMachineActions(t *testing.T) {
+	testName := "TestStateMachineActions"
+	stock := 1
+	ctx := context.TODO()
+	calls := []string{}
+	record := func(name string) Action[orderState, orderEvent] {
+		return func(ctx context.Context, t Transition[orderState, orderEvent]) error {
+			calls = append(calls, fmt.Sprintf("%s:%s->%s", name, t.From, t.To))
+			return nil
+		}
+	}
+
+	sm := newOrderMachine(&stock).
+		OnExit(created, record("exit")).
+		OnEntry(paid, record("entry")).
+		OnEntry(shipped, func(ctx context.Context, t Transition[orderState, orderEvent]) error {
+			return fmt.Errorf("courier unavailable")
+		})
+
+	_ = sm.Fire(ctx, pay)
+	expected := []string{"exit:created->paid", "entry:created->paid"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("%s failed: expected calls %v, got %v", testName, expected, calls)
+	}
+
+	if err := sm.Fire(ctx, ship); err == nil || err.Error() != "courier unavailable" {
+		t.Errorf("%s failed: expected the entry action error, got %v", testName, err)
+	}
+	if sm.Current() != paid || len(sm.History()) != 1 {
+		t.Errorf("%s failed: a failing action must not change the state", testName)
+	}
+}
+
+func TestStateMachineReentrancy(t *testing.T) {
+	testName := "TestStateMachineReentrancy"
+	stock := 1
+	var sm *StateMachine[orderState, orderEvent]
+	var during orderState
+	var canShip bool
+	var reentrant error
+	sm = newOrderMachine(&stock).
+		Permit(created, ship, shipped, func(ctx context.Context, t Transition[orderState, orderEvent]) bool {
+			return sm.Can(ctx, pay)
+		}).
+		OnEntry(paid, func(ctx context.Context, t Transition[orderState, orderEvent]) error {
+			during, canShip = sm.Current(), sm.Can(ctx, ship)
+			reentrant = sm.Fire(ctx, ship)
+			return nil
+		})
+
+	done := make(chan error)
+	go func() {
+		done <- sm.Fire(context.TODO(), pay)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("%s failed: unexpected error %v", testName, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("%s failed: the actions reading the machine deadlock", testName)
+	}
+	if during != created || !canShip {
+		t.Errorf("%s failed: expected the actions to see the current state, got %s (can ship %t)", testName, during, canShip)
+	}
+	if !errors.Is(reentrant, ErrReentrantFire) {
+		t.Errorf("%s failed: expected %v from the action, got %v", testName, ErrReentrantFire, reentrant)
+	}
+	// the follow-up event is fired after the transition
+	if err := sm.Fire(context.TODO(), ship); err != nil || sm.Current() != shipped {
+		t.Errorf("%s failed: expected the follow-up event to be applied, got %v in %s", testName, err, sm.Current())
+	}
+}
+
+func TestStateMachineExport(t *testing.T) {
+	testName := "TestStateMachineExport"
+	stock := 1
+	sm := newOrderMachine(&stock)
+
+	dot := sm.ToDOT()
+	for _, expected := range []string{
+		`__start -> "created";`,
+		`"created" -> "paid" [label="pay"];`,
+		`"paid" -> "shipped" [label="ship", style=dashed];`,
+	} {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("%s failed: expected DOT output to contain %s, got\n%s", testName, expected, dot)
+		}
+	}
+
+	mermaid := sm.ToMermaid()
+	for _, expected := range []string{
+		"stateDiagram-v2",
+		"[*] --> created",
+		"created --> cancelled : cancel",
+		"paid --> shipped : ship [guarded]",
+	} {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("%s failed: expected Mermaid output to contain %s, got\n%s", testName, expected, mermaid)
+		}
+	}
+
+	spaced := New[string, string]("in progress").Permit("in progress", "done", "completed")
+	if mermaid := spaced.ToMermaid(); !strings.Contains(mermaid, `state "in progress" as in_progress_0`) {
+		t.Errorf("%s failed: expected states with spaces to be aliased, got\n%s", testName, mermaid)
+	}
+}
@@ -0,0 +1,74 @@
+package fsm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var mermaidInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// ToDOT exports the transition graph in the Graphviz DOT language.
+// Guarded transitions are drawn with a dashed edge.
+func (sm *StateMachine[S, E]) ToDOT() string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("digraph fsm {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\t__start [shape=point];\n")
+	fmt.Fprintf(&b, "\t__start -> %s;\n", dotQuote(sm.initial))
+	for _, e := range sm.edges {
+		style := ""
+		if e.guarded {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s%s];\n", dotQuote(e.from), dotQuote(e.to), dotQuote(e.event), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// ToMermaid exports the transition graph as a Mermaid state diagram.
+// Guarded transitions are labelled with a trailing "[guarded]".
+func (sm *StateMachine[S, E]) ToMermaid() string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+
+	// mermaid identifiers cannot contain spaces or punctuation: states are declared with an alias
+	ids := map[string]string{}
+	id := func(state S) string {
+		label := fmt.Sprint(state)
+		if v, ok := ids[label]; ok {
+			return v
+		}
+		v := mermaidInvalidChars.ReplaceAllString(label, "_")
+		if v != label {
+			v = fmt.Sprintf("%s_%d", v, len(ids))
+			fmt.Fprintf(&b, "\tstate %q as %s\n", label, v)
+		}
+		ids[label] = v
+		return v
+	}
+
+	lines := []string{fmt.Sprintf("\t[*] --> %s\n", id(sm.initial))}
+	for _, e := range sm.edges {
+		label := fmt.Sprint(e.event)
+		if e.guarded {
+			label += " [guarded]"
+		}
+		lines = append(lines, fmt.Sprintf("\t%s --> %s : %s\n", id(e.from), id(e.to), label))
+	}
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+func dotQuote(v any) string {
+	return `"` + strings.ReplaceAll(fmt.Sprint(v), `"`, `\"`) + `"`
+}
@@ -1,5 +1,8 @@
 module github.com/gyozatech/sushi
 
-go 1.19
+go 1.21
 
-require github.com/jmoiron/sqlx v1.4.0
+require (
+	github.com/jmoiron/sqlx v1.4.0
+	golang.org/x/crypto v0.33.0
+)
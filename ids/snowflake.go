@@ -0,0 +1,206 @@
+package ids
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Snowflake is a 64-bit time-ordered identifier made of, from the most significant bits,
+// the milliseconds elapsed since an epoch, the number of the node which generated it and a sequence number
+type Snowflake int64
+
+// DefaultEpoch is the epoch of the Snowflake generators unless configured otherwise
+var DefaultEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrClockSkew is returned by a SnowflakeGenerator when the clock is before its epoch
+// or moved backwards by more than the maximum skew
+var ErrClockSkew = errors.New("ids: clock skew")
+
+// SnowflakeOption customizes a SnowflakeGenerator
+type SnowflakeOption func(*SnowflakeGenerator)
+
+// WithEpoch sets the time from which the timestamps of the identifiers are counted
+func WithEpoch(epoch time.Time) SnowflakeOption {
+	return func(g *SnowflakeGenerator) {
+		g.epoch = epoch.UnixMilli()
+	}
+}
+
+// WithNodeBits sets the number of bits of the node number (10 by default)
+func WithNodeBits(bits int) SnowflakeOption {
+	return func(g *SnowflakeGenerator) {
+		g.nodeBits = bits
+	}
+}
+
+// WithSequenceBits sets the number of bits of the sequence number (12 by default),
+// i.e. how many identifiers a node can generate per millisecond
+func WithSequenceBits(bits int) SnowflakeOption {
+	return func(g *SnowflakeGenerator) {
+		g.sequenceBits = bits
+	}
+}
+
+// WithMaxClockSkew sets how far backwards the clock can move (10 milliseconds by default): within it the generator
+// keeps counting from the last timestamp, waiting for the clock to catch up when the sequence is exhausted
+func WithMaxClockSkew(skew time.Duration) SnowflakeOption {
+	return func(g *SnowflakeGenerator) {
+		g.maxSkew = skew.Milliseconds()
+	}
+}
+
+// SnowflakeGenerator generates the Snowflake identifiers of a node
+type SnowflakeGenerator struct {
+	mu           sync.Mutex
+	epoch        int64
+	maxSkew      int64
+	node         int64
+	nodeBits     int
+	sequenceBits int
+	lastMillis   int64
+	sequence     int64
+}
+
+// NewSnowflakeGenerator returns the generator of the given node, which must fit in the node bits
+func NewSnowflakeGenerator(node int64, options ...SnowflakeOption) (*SnowflakeGenerator, error) {
+	g := &SnowflakeGenerator{
+		epoch:        DefaultEpoch.UnixMilli(),
+		maxSkew:      10,
+		node:         node,
+		nodeBits:     10,
+		sequenceBits: 12,
+	}
+	for _, option := range options {
+		option(g)
+	}
+	if g.nodeBits < 0 || g.sequenceBits < 0 || g.nodeBits+g.sequenceBits > 22 {
+		return nil, fmt.Errorf("ids: node and sequence bits must be at most 22, got %d and %d", g.nodeBits, g.sequenceBits)
+	}
+	if g.maxSkew < 0 {
+		return nil, fmt.Errorf("ids: negative maximum clock skew %dms", g.maxSkew)
+	}
+	if node < 0 || node >= 1<<g.nodeBits {
+		return nil, fmt.Errorf("ids: node %d doesn't fit in %d bits", node, g.nodeBits)
+	}
+	return g, nil
+}
+
+// Next returns a new identifier, greater than all the ones previously generated by the generator.
+// When the sequence of the current millisecond is exhausted it waits for the next one,
+// and if the clock moves backwards within the maximum skew it keeps counting from the last timestamp.
+// It returns ErrClockSkew, without waiting, if the clock is before the epoch or further behind.
+func (g *SnowflakeGenerator) Next() (Snowflake, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis, err := g.millis()
+	if err != nil {
+		return 0, err
+	}
+	var sequence int64
+	if millis <= g.lastMillis {
+		millis = g.lastMillis
+		sequence = (g.sequence + 1) & (1<<g.sequenceBits - 1)
+		if sequence == 0 {
+			if millis, err = g.waitAfter(g.lastMillis); err != nil {
+				return 0, err
+			}
+		}
+	}
+	g.lastMillis, g.sequence = millis, sequence
+	return Snowflake(millis<<(g.nodeBits+g.sequenceBits) | g.node<<g.sequenceBits | sequence), nil
+}
+
+// millis returns the milliseconds elapsed since the epoch, checking the clock skew
+func (g *SnowflakeGenerator) millis() (int64, error) {
+	millis := now().UnixMilli() - g.epoch
+	if millis < 0 {
+		return 0, fmt.Errorf("%w: the clock is %dms before the epoch", ErrClockSkew, -millis)
+	}
+	if g.lastMillis-millis > g.maxSkew {
+		return 0, fmt.Errorf("%w: the clock moved backwards by %dms", ErrClockSkew, g.lastMillis-millis)
+	}
+	return millis, nil
+}
+
+// waitAfter waits for the clock to reach a millisecond after the given one: the wait is bounded by the maximum skew
+func (g *SnowflakeGenerator) waitAfter(millis int64) (int64, error) {
+	for {
+		current, err := g.millis()
+		if err != nil || current > millis {
+			return current, err
+		}
+		time.Sleep(time.Duration(millis-current+1) * time.Millisecond)
+	}
+}
+
+// Time returns the creation time of an identifier generated with the same epoch and layout of the generator
+func (g *SnowflakeGenerator) Time(id Snowflake) time.Time {
+	return time.UnixMilli(int64(id)>>(g.nodeBits+g.sequenceBits) + g.epoch)
+}
+
+// Node returns the node which generated an identifier
+func (g *SnowflakeGenerator) Node(id Snowflake) int64 {
+	return int64(id) >> g.sequenceBits & (1<<g.nodeBits - 1)
+}
+
+// Sequence returns the sequence number of an identifier
+func (g *SnowflakeGenerator) Sequence(id Snowflake) int64 {
+	return int64(id) & (1<<g.sequenceBits - 1)
+}
+
+// ParseSnowflake parses an identifier from its decimal representation
+func ParseSnowflake(s string) (Snowflake, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("%w: snowflake %q", ErrInvalidID, s)
+	}
+	return Snowflake(id), nil
+}
+
+// String returns the decimal representation of the identifier
+func (id Snowflake) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// MarshalText encodes the identifier in decimal: in JSON it's a string,
+// since JavaScript numbers cannot represent all the 64-bit integers
+func (id Snowflake) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText parses the identifier with ParseSnowflake
+func (id *Snowflake) UnmarshalText(text []byte) error {
+	parsed, err := ParseSnowflake(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Value stores the identifier in a database as an integer
+func (id Snowflake) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan reads the identifier from a database column holding an integer or its decimal representation
+func (id *Snowflake) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = 0
+		return nil
+	case int64:
+		*id = Snowflake(v)
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		return id.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: cannot scan %T into a snowflake", ErrInvalidID, src)
+}
@@ -0,0 +1,120 @@
+package ids
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeGenerator(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, &at)
+
+	g, err := NewSnowflakeGenerator(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := g.Next()
+	second, _ := g.Next()
+	if second <= first || g.Node(second) != 5 || g.Sequence(first) != 0 || g.Sequence(second) != 1 || !g.Time(second).Equal(at) {
+		t.Errorf("%s failed: unexpected %d and %d", t.Name(), first, second)
+	}
+
+	at = at.Add(-5 * time.Millisecond) // the clock moves backwards within the maximum skew
+	if third, err := g.Next(); err != nil || third <= second || g.Sequence(third) != 2 {
+		t.Errorf("%s failed (clock backwards): unexpected %d after %d (%v)", t.Name(), third, second, err)
+	}
+
+	at = at.Add(-time.Second) // the clock moves backwards beyond the maximum skew
+	if _, err := g.Next(); !errors.Is(err, ErrClockSkew) {
+		t.Errorf("%s failed (clock skew): expected ErrClockSkew, got %v", t.Name(), err)
+	}
+	at = at.Add(time.Second + 5*time.Millisecond)
+	if fourth, err := g.Next(); err != nil || fourth <= second || g.Sequence(fourth) != 3 {
+		t.Errorf("%s failed (clock restored): unexpected %d after %d (%v)", t.Name(), fourth, second, err)
+	}
+
+	future, err := NewSnowflakeGenerator(5, WithEpoch(at.Add(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := future.Next(); !errors.Is(err, ErrClockSkew) {
+		t.Errorf("%s failed (future epoch): expected ErrClockSkew, got %v", t.Name(), err)
+	}
+}
+
+func TestSnowflakeLayout(t *testing.T) {
+	epoch := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := epoch.Add(3 * time.Millisecond)
+	fixClock(t, &at)
+
+	g, err := NewSnowflakeGenerator(3, WithEpoch(epoch), WithNodeBits(2), WithSequenceBits(1), WithMaxClockSkew(2*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 milliseconds, node 3, sequence 0 and 1: 0b11_11_0 and 0b11_11_1
+	first, _ := g.Next()
+	second, _ := g.Next()
+	if first != 0b11110 || second != 0b11111 {
+		t.Errorf("%s failed: unexpected %b and %b", t.Name(), first, second)
+	}
+
+	// the sequence is exhausted: the generator waits for the clock to reach the next millisecond
+	reads := 0
+	now = func() time.Time {
+		if reads++; reads > 1 {
+			return at.Add(time.Millisecond)
+		}
+		return at
+	}
+	if third, err := g.Next(); err != nil || third != 0b100110 || !g.Time(third).Equal(epoch.Add(4*time.Millisecond)) {
+		t.Errorf("%s failed (wait): unexpected %b (%v)", t.Name(), third, err)
+	}
+
+	// the sequence is exhausted again and the clock moves backwards beyond the maximum skew: the generator doesn't wait
+	reads = 0
+	now = func() time.Time {
+		if reads++; reads > 2 {
+			return epoch.Add(time.Millisecond)
+		}
+		return at.Add(time.Millisecond)
+	}
+	fourth, _ := g.Next()
+	if _, err := g.Next(); !errors.Is(err, ErrClockSkew) {
+		t.Errorf("%s failed (wait with skew): expected ErrClockSkew, got %v", t.Name(), err)
+	}
+	now = func() time.Time { return at.Add(2 * time.Millisecond) }
+	if fifth, err := g.Next(); err != nil || fifth <= fourth || g.Sequence(fifth) != 0 {
+		t.Errorf("%s failed (after the skew): unexpected %b after %b (%v)", t.Name(), fifth, fourth, err)
+	}
+
+	for _, options := range [][]SnowflakeOption{{WithNodeBits(2)}, {WithNodeBits(20), WithSequenceBits(5)}, {WithNodeBits(-1)}, {WithMaxClockSkew(-time.Millisecond)}} {
+		if _, err := NewSnowflakeGenerator(4, options...); err == nil {
+			t.Errorf("%s failed: expected an invalid configuration", t.Name())
+		}
+	}
+}
+
+func TestSnowflakeEncoding(t *testing.T) {
+	id := Snowflake(1786393919318016005)
+	data, err := json.Marshal(struct {
+		ID Snowflake `json:"id"`
+	}{ID: id})
+	if err != nil || string(data) != `{"id":"1786393919318016005"}` {
+		t.Fatalf("%s failed (JSON): unexpected %s (%v)", t.Name(), data, err)
+	}
+
+	for _, src := range []any{int64(id), "1786393919318016005", []byte("1786393919318016005")} {
+		var scanned Snowflake
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("%s failed (Scan %T): expected %d, got %d (%v)", t.Name(), src, id, scanned, err)
+		}
+	}
+	if value, _ := id.Value(); value != int64(id) {
+		t.Errorf("%s failed (Value): unexpected %v", t.Name(), value)
+	}
+	if _, err := ParseSnowflake("-1"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("%s failed: expected ErrInvalidID, got %v", t.Name(), err)
+	}
+}
@@ -0,0 +1,196 @@
+package ids
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrULIDOverflow is returned by a monotonic ULIDGenerator when the random part cannot be incremented
+// within the same millisecond anymore
+var ErrULIDOverflow = errors.New("ids: ULID random part overflow")
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier:
+// 48 bits of Unix time in milliseconds followed by 80 random bits, encoded in 26 characters of Crockford's base32
+type ULID [16]byte
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordValues maps the characters accepted when decoding to their value, -1 for invalid ones
+var crockfordValues = func() [256]int8 {
+	var values [256]int8
+	for i := range values {
+		values[i] = -1
+	}
+	for i, c := range crockford {
+		values[c] = int8(i)
+		values[c|0x20] = int8(i) // lowercase
+	}
+	for _, alias := range []struct {
+		c     byte
+		value int8
+	}{{'O', 0}, {'o', 0}, {'I', 1}, {'i', 1}, {'L', 1}, {'l', 1}} {
+		values[alias.c] = alias.value
+	}
+	return values
+}()
+
+// ULIDGenerator generates ULIDs: in monotonic mode the ULIDs generated within the same millisecond
+// increment the random part of the previous one, so that they are strictly increasing
+type ULIDGenerator struct {
+	mu         sync.Mutex
+	monotonic  bool
+	lastMillis int64
+	last       ULID
+}
+
+// NewULIDGenerator returns a ULID generator, monotonic or not
+func NewULIDGenerator(monotonic bool) *ULIDGenerator {
+	return &ULIDGenerator{monotonic: monotonic}
+}
+
+var defaultULIDGenerator = NewULIDGenerator(true)
+
+// NewULID returns a ULID from a monotonic generator shared by the process.
+// It panics in the unlikely event of an overflow: use a ULIDGenerator to handle it.
+func NewULID() ULID {
+	id, err := defaultULIDGenerator.New()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// New returns a new ULID with the current time
+func (g *ULIDGenerator) New() (ULID, error) {
+	var id ULID
+	millis := now().UnixMilli()
+	if !g.monotonic {
+		putMillis(id[:6], millis)
+		randomBytes(id[6:])
+		return id, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if millis <= g.lastMillis {
+		// same millisecond or clock moved backwards: the random part of the last ULID is incremented
+		id = g.last
+		for i := len(id) - 1; ; i-- {
+			if i < 6 {
+				return ULID{}, ErrULIDOverflow
+			}
+			id[i]++
+			if id[i] != 0 {
+				break
+			}
+		}
+	} else {
+		putMillis(id[:6], millis)
+		randomBytes(id[6:])
+		g.lastMillis = millis
+	}
+	g.last = id
+	return id, nil
+}
+
+// ParseULID parses a ULID from its 26 characters, case-insensitively
+func ParseULID(s string) (ULID, error) {
+	var id ULID
+	if len(s) != 26 || crockfordValues[s[0]] > 7 {
+		return id, fmt.Errorf("%w: ULID %q", ErrInvalidID, s)
+	}
+	// the 26 characters hold 130 bits: the 128 bits of the ULID are accumulated in two halves
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValues[s[i]]
+		if v < 0 {
+			return ULID{}, fmt.Errorf("%w: ULID %q", ErrInvalidID, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	for i := 0; i < 8; i++ {
+		id[i] = byte(hi >> (56 - 8*i))
+		id[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return id, nil
+}
+
+// MustParseULID is like ParseULID but panics if the string cannot be parsed
+func MustParseULID(s string) ULID {
+	id, err := ParseULID(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// String returns the 26 characters of the ULID
+func (id ULID) String() string {
+	var hi, lo uint64
+	for i := 0; i < 8; i++ {
+		hi = hi<<8 | uint64(id[i])
+		lo = lo<<8 | uint64(id[8+i])
+	}
+	var b [26]byte
+	for i := range b {
+		shift := uint(125 - 5*i)
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift == 0:
+			v = lo
+		default:
+			v = hi<<(64-shift) | lo>>shift
+		}
+		b[i] = crockford[v&31]
+	}
+	return string(b[:])
+}
+
+// Time returns the creation time of the ULID
+func (id ULID) Time() time.Time {
+	return time.UnixMilli(readMillis(id[:6]))
+}
+
+// MarshalText encodes the ULID in its textual form, which is also its JSON representation
+func (id ULID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText parses the ULID with ParseULID
+func (id *ULID) UnmarshalText(text []byte) error {
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Value stores the ULID in a database in its textual form
+func (id ULID) Value() (driver.Value, error) {
+	return id.String(), nil
+}
+
+// Scan reads the ULID from a database column holding its textual form or its 16 bytes: NULL is read as the zero ULID
+func (id *ULID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ULID{}
+		return nil
+	case string:
+		return id.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(id) {
+			copy(id[:], v)
+			return nil
+		}
+		return id.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: cannot scan %T into a ULID", ErrInvalidID, src)
+}
@@ -0,0 +1,83 @@
+package ids
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseULID(t *testing.T) {
+	id, err := ParseULID("01ARYZ6S41TSV4RRFFQ69G5FAV")
+	if err != nil || id.Time().UnixMilli() != 1469918176385 {
+		t.Fatalf("%s failed: unexpected %v (%v)", t.Name(), id.Time(), err)
+	}
+	if id.String() != "01ARYZ6S41TSV4RRFFQ69G5FAV" {
+		t.Errorf("%s failed: expected the same string back, got %s", t.Name(), id)
+	}
+	if lower := MustParseULID("01aryz6s41tsv4rrffq69g5fav"); lower != id {
+		t.Errorf("%s failed: expected the lowercase ULID to be equal, got %s", t.Name(), lower)
+	}
+	if max := MustParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ"); max != (ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("%s failed: unexpected maximum ULID %v", t.Name(), max)
+	}
+
+	for _, s := range []string{"", "01ARYZ6S41TSV4RRFFQ69G5FA", "81ARYZ6S41TSV4RRFFQ69G5FAV", "01ARYZ6S41TSV4RRFFQ69G5FAU"} {
+		if _, err := ParseULID(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("%s failed (%s): expected ErrInvalidID, got %v", t.Name(), s, err)
+		}
+	}
+}
+
+func TestULIDGenerator(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, &at)
+
+	g := NewULIDGenerator(true)
+	previous, _ := g.New()
+	for i := 0; i < 1000; i++ {
+		id, err := g.New()
+		if err != nil || id.String() <= previous.String() || !id.Time().Equal(at) {
+			t.Fatalf("%s failed: %s (%v) generated after %s", t.Name(), id, err, previous)
+		}
+		previous = id
+	}
+
+	// the random part of the last ULID is at its maximum
+	for i := 6; i < 16; i++ {
+		g.last[i] = 0xff
+	}
+	if _, err := g.New(); !errors.Is(err, ErrULIDOverflow) {
+		t.Errorf("%s failed: expected ErrULIDOverflow, got %v", t.Name(), err)
+	}
+
+	random := NewULIDGenerator(false)
+	a, _ := random.New()
+	b, _ := random.New()
+	if a == b || a.Time() != b.Time() {
+		t.Errorf("%s failed (not monotonic): unexpected %s and %s", t.Name(), a, b)
+	}
+	if NewULID() == NewULID() {
+		t.Errorf("%s failed: expected different ULIDs", t.Name())
+	}
+}
+
+func TestULIDEncoding(t *testing.T) {
+	id := NewULID()
+	data, err := json.Marshal(map[string]ULID{"id": id})
+	if err != nil || string(data) != `{"id":"`+id.String()+`"}` {
+		t.Fatalf("%s failed (JSON): unexpected %s (%v)", t.Name(), data, err)
+	}
+	var decoded map[string]ULID
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["id"] != id {
+		t.Errorf("%s failed (JSON): expected %s, got %s (%v)", t.Name(), id, decoded["id"], err)
+	}
+
+	value, _ := id.Value()
+	for _, src := range []any{value, id[:]} {
+		var scanned ULID
+		if err := scanned.Scan(src); err != nil || scanned != id {
+			t.Errorf("%s failed (Scan %T): expected %s, got %s (%v)", t.Name(), src, id, scanned, err)
+		}
+	}
+}
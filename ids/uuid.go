@@ -0,0 +1,202 @@
+package ids
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidID is returned when parsing or scanning malformed identifiers
+var ErrInvalidID = errors.New("ids: invalid identifier")
+
+// UUID is a universally unique identifier as defined by RFC 9562
+type UUID [16]byte
+
+// Nil is the UUID with all bits set to zero
+var Nil UUID
+
+// uuidV7 keeps the state making the version 7 UUIDs monotonic
+var uuidV7 struct {
+	sync.Mutex
+	lastMillis int64
+	counter    uint16
+}
+
+// now is the clock of the generators, replaced by the tests
+var now = time.Now
+
+// NewV4 returns a random (version 4) UUID
+func NewV4() UUID {
+	var u UUID
+	randomBytes(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// NewV7 returns a time-ordered (version 7) UUID: its first 48 bits are the Unix time in milliseconds,
+// followed by a 12-bit counter which keeps the UUIDs generated by the process strictly increasing
+// within the same millisecond, and by 62 random bits
+func NewV7() UUID {
+	var u UUID
+	randomBytes(u[:])
+
+	uuidV7.Lock()
+	millis := now().UnixMilli()
+	if millis <= uuidV7.lastMillis {
+		// same millisecond or clock moved backwards: the counter goes on from the last UUID
+		millis = uuidV7.lastMillis
+		uuidV7.counter++
+		if uuidV7.counter > 0xfff {
+			millis++
+			uuidV7.counter = 0
+		}
+	} else {
+		// the counter starts from a random value leaving room for increments
+		uuidV7.counter = binary.BigEndian.Uint16(u[6:8]) & 0x7ff
+	}
+	uuidV7.lastMillis = millis
+	counter := uuidV7.counter
+	uuidV7.Unlock()
+
+	putMillis(u[:6], millis)
+	u[6] = 0x70 | byte(counter>>8)
+	u[7] = byte(counter)
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
+
+// ParseUUID parses a UUID in its canonical form (xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx),
+// also without hyphens, wrapped in braces or prefixed by urn:uuid:
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	text := s
+	switch {
+	case len(text) == 45 && strings.EqualFold(text[:9], "urn:uuid:"):
+		text = text[9:]
+	case len(text) == 38 && text[0] == '{' && text[37] == '}':
+		text = text[1:37]
+	}
+	if len(text) == 36 {
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return Nil, fmt.Errorf("%w: UUID %q", ErrInvalidID, s)
+		}
+		text = text[:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	}
+	if len(text) != 32 {
+		return Nil, fmt.Errorf("%w: UUID %q", ErrInvalidID, s)
+	}
+	if _, err := hex.Decode(u[:], []byte(text)); err != nil {
+		return Nil, fmt.Errorf("%w: UUID %q", ErrInvalidID, s)
+	}
+	return u, nil
+}
+
+// MustParseUUID is like ParseUUID but panics if the string cannot be parsed
+func MustParseUUID(s string) UUID {
+	u, err := ParseUUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String returns the canonical form of the UUID
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Version returns the version of the UUID, e.g. 4 or 7
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsNil returns true for the Nil UUID
+func (u UUID) IsNil() bool {
+	return u == Nil
+}
+
+// Time returns the creation time of a version 7 UUID, false for the other versions
+func (u UUID) Time() (time.Time, bool) {
+	if u.Version() != 7 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(readMillis(u[:6])), true
+}
+
+// MarshalText encodes the UUID in its canonical form, which is also its JSON representation
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses the UUID with ParseUUID
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Value stores the UUID in a database in its canonical form
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan reads the UUID from a database column holding its textual form or its 16 bytes: NULL is read as Nil
+func (u *UUID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*u = Nil
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(u) {
+			copy(u[:], v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: cannot scan %T into a UUID", ErrInvalidID, src)
+}
+
+// randomBytes fills b with bytes read from crypto/rand
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("ids: cannot read random bytes: %v", err))
+	}
+}
+
+// putMillis writes a 48-bit big-endian timestamp
+func putMillis(b []byte, millis int64) {
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(millis)
+		millis >>= 8
+	}
+}
+
+func readMillis(b []byte) int64 {
+	var millis int64
+	for _, x := range b[:6] {
+		millis = millis<<8 | int64(x)
+	}
+	return millis
+}
@@ -0,0 +1,107 @@
+package ids
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fixClock makes the generators read the given time, restoring the real clock at the end of the test
+func fixClock(t *testing.T, at *time.Time) {
+	uuidV7.lastMillis = 0
+	now = func() time.Time { return *at }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestNewV4(t *testing.T) {
+	seen := map[UUID]bool{}
+	for i := 0; i < 1000; i++ {
+		u := NewV4()
+		if u.Version() != 4 || u[8]&0xc0 != 0x80 || seen[u] {
+			t.Fatalf("%s failed: unexpected UUID %s", t.Name(), u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestNewV7(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, &at)
+
+	previous := NewV7()
+	for i := 0; i < 5000; i++ {
+		u := NewV7()
+		if u.Version() != 7 || u[8]&0xc0 != 0x80 {
+			t.Fatalf("%s failed: unexpected UUID %s", t.Name(), u)
+		}
+		if u.String() <= previous.String() {
+			t.Fatalf("%s failed: %s generated after %s within the same millisecond", t.Name(), u, previous)
+		}
+		previous = u
+	}
+
+	at = at.Add(time.Second)
+	u := NewV7()
+	if created, ok := u.Time(); !ok || !created.Equal(at) {
+		t.Errorf("%s failed: expected time %v, got %v", t.Name(), at, created)
+	}
+	if _, ok := NewV4().Time(); ok {
+		t.Errorf("%s failed: expected no time for a version 4 UUID", t.Name())
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	canonical := "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+	for _, s := range []string{
+		canonical,
+		"017F22E2-79B0-7CC3-98C4-DC0C0C07398F",
+		"017f22e279b07cc398c4dc0c0c07398f",
+		"{017f22e2-79b0-7cc3-98c4-dc0c0c07398f}",
+		"urn:uuid:017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+	} {
+		u, err := ParseUUID(s)
+		if err != nil || u.String() != canonical {
+			t.Errorf("%s failed (%s): expected %s, got %s (%v)", t.Name(), s, canonical, u, err)
+		}
+	}
+	if created, _ := MustParseUUID(canonical).Time(); created.UnixMilli() != 1645557742000 {
+		t.Errorf("%s failed: unexpected time %v", t.Name(), created)
+	}
+
+	for _, s := range []string{"", "017f22e2-79b0-7cc3-98c4-dc0c0c07398", "017f22e2079b0-7cc3-98c4-dc0c0c07398f", "017f22e2-79b0-7cc3-98c4-dc0c0c07398g", "[017f22e2-79b0-7cc3-98c4-dc0c0c07398f]"} {
+		if _, err := ParseUUID(s); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("%s failed (%s): expected ErrInvalidID, got %v", t.Name(), s, err)
+		}
+	}
+}
+
+func TestUUIDEncoding(t *testing.T) {
+	type payload struct {
+		ID UUID `json:"id"`
+	}
+	u := NewV7()
+	data, err := json.Marshal(payload{ID: u})
+	if err != nil || string(data) != `{"id":"`+u.String()+`"}` {
+		t.Fatalf("%s failed (JSON): unexpected %s (%v)", t.Name(), data, err)
+	}
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.ID != u {
+		t.Errorf("%s failed (JSON): expected %s, got %s (%v)", t.Name(), u, decoded.ID, err)
+	}
+
+	value, _ := u.Value()
+	for _, src := range []any{value, []byte(u.String()), u[:]} {
+		var scanned UUID
+		if err := scanned.Scan(src); err != nil || scanned != u {
+			t.Errorf("%s failed (Scan %T): expected %s, got %s (%v)", t.Name(), src, u, scanned, err)
+		}
+	}
+	scanned := u
+	if err := scanned.Scan(nil); err != nil || !scanned.IsNil() {
+		t.Errorf("%s failed (Scan nil): expected Nil, got %s (%v)", t.Name(), scanned, err)
+	}
+	if err := scanned.Scan(42); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("%s failed (Scan int): expected ErrInvalidID, got %v", t.Name(), err)
+	}
+}
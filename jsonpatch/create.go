@@ -0,0 +1,54 @@
+package jsonpatch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// Create returns the JSON Patch which turns the JSON representation of a into the one of b,
+// computed from the structural differences found by utils.Diff
+func Create(a, b any) (Patch, error) {
+	na, err := normalize(a)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: %w", err)
+	}
+	nb, err := normalize(b)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: %w", err)
+	}
+
+	diffs := utils.Diff(na, nb)
+	patch := Patch{}
+	for i := 0; i < len(diffs); i++ {
+		d := diffs[i]
+		switch d.Kind {
+		case utils.Added:
+			patch = append(patch, Operation{Op: "add", Path: d.Path, Value: d.B})
+		case utils.Changed:
+			patch = append(patch, Operation{Op: "replace", Path: d.Path, Value: d.B})
+		case utils.Removed:
+			// the trailing elements of an array are reported in ascending order:
+			// they are removed from the last one, so that the indexes of the others don't shift
+			run := i
+			for run+1 < len(diffs) && diffs[run+1].Kind == utils.Removed && parent(diffs[run+1].Path) == parent(d.Path) {
+				run++
+			}
+			for j := run; j >= i; j-- {
+				patch = append(patch, Operation{Op: "remove", Path: diffs[j].Path})
+			}
+			i = run
+		}
+	}
+
+	// the diff doesn't tell null from empty arrays and objects: in that case the whole document is replaced
+	if patched, err := patch.apply(deepCopy(na)); err != nil || !equal(patched, nb) {
+		return Patch{{Op: "replace", Path: "", Value: nb}}, nil
+	}
+	return patch, nil
+}
+
+func parent(pointer string) string {
+	return pointer[:strings.LastIndexByte(pointer, '/')+1]
+}
@@ -0,0 +1,202 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// isPrefix returns true if the location at path is a child of the one at parent
+func isPrefix(parent, path []string) bool {
+	if len(parent) >= len(path) {
+		return false
+	}
+	for i := range parent {
+		if parent[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// index parses an array index: "-" refers to the position after the last element and is accepted only when appending
+func index(token string, length int, appending bool) (int, error) {
+	if token == "-" && appending {
+		return length, nil
+	}
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidIndex, token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i > length || (i == length && !appending) {
+		return 0, fmt.Errorf("%w: %q out of bounds", ErrInvalidIndex, token)
+	}
+	return i, nil
+}
+
+func get(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = child
+		case []any:
+			i, err := index(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return node, nil
+}
+
+// update replaces the parent of the location at path with the result of change, returning the new root
+func update(root any, path []string, change func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return change(root, path[0])
+	}
+	token := path[0]
+	child, err := get(root, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	if child, err = update(child, path[1:], change); err != nil {
+		return nil, err
+	}
+	switch n := root.(type) {
+	case map[string]any:
+		n[token] = child
+	case []any:
+		i, _ := index(token, len(n), false)
+		n[i] = child
+	}
+	return root, nil
+}
+
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(root, path, func(parent any, token string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			n[token] = value
+			return n, nil
+		case []any:
+			i, err := index(token, len(n), true)
+			if err != nil {
+				return nil, err
+			}
+			n = append(n, nil)
+			copy(n[i+1:], n[i:])
+			n[i] = value
+			return n, nil
+		}
+		return nil, ErrPathNotFound
+	})
+}
+
+func replace(root any, path []string, value any) (any, error) {
+	if _, err := get(root, path); err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return value, nil
+	}
+	return update(root, path, func(parent any, token string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			n[token] = value
+		case []any:
+			i, _ := index(token, len(n), false)
+			n[i] = value
+		}
+		return parent, nil
+	})
+}
+
+// remove deletes the value at path, returning the new root and the removed value
+func remove(root any, path []string) (any, any, error) {
+	removed, err := get(root, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(path) == 0 {
+		return nil, removed, nil
+	}
+	root, err = update(root, path, func(parent any, token string) (any, error) {
+		switch n := parent.(type) {
+		case map[string]any:
+			delete(n, token)
+		case []any:
+			i, _ := index(token, len(n), false)
+			return append(n[:i:i], n[i+1:]...), nil
+		}
+		return parent, nil
+	})
+	return root, removed, err
+}
+
+func deepCopy(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(n))
+		for k, v := range n {
+			result[k] = deepCopy(v)
+		}
+		return result
+	case []any:
+		result := make([]any, len(n))
+		for i, v := range n {
+			result[i] = deepCopy(v)
+		}
+		return result
+	}
+	return node
+}
+
+// equal compares two generic JSON values, numbers by their value regardless of their representation
+func equal(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			other, ok := b[k]
+			if !ok || !equal(v, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, okX := new(big.Rat).SetString(a.String())
+		y, okY := new(big.Rat).SetString(b.String())
+		return okX && okY && x.Cmp(y) == 0
+	}
+	return a == b
+}
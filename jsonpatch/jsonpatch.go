@@ -0,0 +1,273 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+var (
+	// ErrInvalidOperation is returned for the operations with an unknown op or missing members
+	ErrInvalidOperation = errors.New("invalid operation")
+	// ErrInvalidPointer is returned for the malformed JSON pointers
+	ErrInvalidPointer = utils.ErrInvalidPointer
+	// ErrPathNotFound is returned when the location targeted by an operation doesn't exist
+	ErrPathNotFound = errors.New("path not found")
+	// ErrInvalidIndex is returned for the array indexes which are malformed or out of bounds
+	ErrInvalidIndex = errors.New("invalid array index")
+	// ErrTestFailed is returned when the value of a test operation doesn't match the document
+	ErrTestFailed = errors.New("test failed")
+)
+
+// Error describes the operation of a patch which failed
+type Error struct {
+	// Index is the position of the operation in the patch
+	Index int
+	Op    string
+	// Path is the JSON pointer which caused the failure: the path or the from member of the operation
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonpatch: operation %d (%s %q): %v", e.Index, e.Op, e.Path, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Operation is a single operation of a JSON Patch (RFC 6902)
+type Operation struct {
+	// Op is one of add, remove, replace, move, copy and test
+	Op   string
+	Path string
+	// From is the source location of move and copy operations
+	From string
+	// Value is the value of add, replace and test operations: any value which can be marshaled to JSON
+	Value any
+}
+
+type rawOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalJSON encodes the operation including only the members defined for its op
+func (o Operation) MarshalJSON() ([]byte, error) {
+	raw := rawOperation{Op: o.Op, Path: &o.Path}
+	switch o.Op {
+	case "move", "copy":
+		raw.From = &o.From
+	case "add", "replace", "test":
+		value, err := json.Marshal(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		raw.Value = value
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes the operation checking that the members required by its op are present
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw rawOperation
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	missing := func(member string) error {
+		return fmt.Errorf("%w: %s operation without %s", ErrInvalidOperation, raw.Op, member)
+	}
+	if raw.Path == nil {
+		return missing("path")
+	}
+	*o = Operation{Op: raw.Op, Path: *raw.Path}
+	switch raw.Op {
+	case "remove":
+	case "move", "copy":
+		if raw.From == nil {
+			return missing("from")
+		}
+		o.From = *raw.From
+	case "add", "replace", "test":
+		if raw.Value == nil {
+			return missing("value")
+		}
+		value, err := decode(raw.Value)
+		if err != nil {
+			return err
+		}
+		o.Value = value
+	default:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidOperation, raw.Op)
+	}
+	return nil
+}
+
+// Patch is a JSON Patch document: a list of operations applied in order
+type Patch []Operation
+
+// DecodePatch parses a JSON Patch document
+func DecodePatch(data []byte) (Patch, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, fmt.Errorf("jsonpatch: %w", err)
+	}
+	patch := make(Patch, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &patch[i]); err != nil {
+			return nil, &Error{Index: i, Op: patch[i].Op, Path: patch[i].Path, Err: err}
+		}
+	}
+	return patch, nil
+}
+
+// Apply decodes a JSON Patch and applies it to a JSON document
+func Apply(doc, patch []byte) ([]byte, error) {
+	p, err := DecodePatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	return p.Apply(doc)
+}
+
+// Apply applies the patch to a JSON document, returning the patched document.
+// The patch is atomic: if any operation fails the error is returned and no document is produced.
+func (p Patch) Apply(doc []byte) ([]byte, error) {
+	root, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: invalid document: %w", err)
+	}
+	if root, err = p.apply(root); err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+// ApplyTo applies the patch to the value pointed by target, going through its JSON representation:
+// target is only modified if all the operations succeed
+func (p Patch) ApplyTo(target any) error {
+	return throughJSON(target, func(doc []byte) ([]byte, error) {
+		return p.Apply(doc)
+	})
+}
+
+func (p Patch) apply(root any) (any, error) {
+	for i, op := range p {
+		var err error
+		path := op.Path
+		if root, path, err = applyOperation(root, op); err != nil {
+			return nil, &Error{Index: i, Op: op.Op, Path: path, Err: err}
+		}
+	}
+	return root, nil
+}
+
+// applyOperation applies a single operation, returning the new root and the pointer to blame on failure
+func applyOperation(root any, op Operation) (any, string, error) {
+	path, err := utils.ParsePointer(op.Path)
+	if err != nil {
+		return nil, op.Path, err
+	}
+	var from []string
+	if op.Op == "move" || op.Op == "copy" {
+		if from, err = utils.ParsePointer(op.From); err != nil {
+			return nil, op.From, err
+		}
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := normalize(op.Value)
+		if err != nil {
+			return nil, op.Path, fmt.Errorf("%w: %v", ErrInvalidOperation, err)
+		}
+		switch op.Op {
+		case "add":
+			root, err = add(root, path, value)
+		case "replace":
+			root, err = replace(root, path, value)
+		default:
+			var current any
+			if current, err = get(root, path); err == nil && !equal(current, value) {
+				err = ErrTestFailed
+			}
+		}
+		return root, op.Path, err
+	case "remove":
+		root, _, err = remove(root, path)
+		return root, op.Path, err
+	case "move":
+		if op.From == op.Path {
+			_, err := get(root, from)
+			return root, op.From, err
+		}
+		if isPrefix(from, path) {
+			return nil, op.From, fmt.Errorf("%w: cannot move a value into one of its children", ErrInvalidOperation)
+		}
+		var value any
+		if root, value, err = remove(root, from); err != nil {
+			return nil, op.From, err
+		}
+		root, err = add(root, path, value)
+		return root, op.Path, err
+	case "copy":
+		value, err := get(root, from)
+		if err != nil {
+			return nil, op.From, err
+		}
+		root, err = add(root, path, deepCopy(value))
+		return root, op.Path, err
+	}
+	return nil, op.Path, fmt.Errorf("%w: unknown op %q", ErrInvalidOperation, op.Op)
+}
+
+// decode parses JSON keeping the numbers as json.Number, so that they don't lose precision
+func decode(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// normalize converts any value into its generic JSON representation
+func normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// throughJSON marshals target, transforms its JSON and unmarshals the result into a fresh value of the same type
+func throughJSON(target any, transform func(doc []byte) ([]byte, error)) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("jsonpatch: expected a non-nil pointer, got %T", target)
+	}
+	doc, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("jsonpatch: %w", err)
+	}
+	if doc, err = transform(doc); err != nil {
+		return err
+	}
+	result := reflect.New(v.Elem().Type())
+	if err := json.Unmarshal(doc, result.Interface()); err != nil {
+		return fmt.Errorf("jsonpatch: cannot decode the patched document: %w", err)
+	}
+	v.Elem().Set(result.Elem())
+	return nil
+}
@@ -0,0 +1,299 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func assertJSON(t *testing.T, testName string, expected string, actual []byte) {
+	t.Helper()
+	e, err := decode([]byte(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := decode(actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal(e, a) {
+		t.Errorf("%s failed (%s): expected %s, got %s", t.Name(), testName, expected, actual)
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		testName string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			testName: "add member",
+			doc:      `{"foo":"bar"}`,
+			patch:    `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected: `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			testName: "add array element",
+			doc:      `{"foo":["bar","baz"]}`,
+			patch:    `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected: `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			testName: "append array element",
+			doc:      `{"foo":[1]}`,
+			patch:    `[{"op":"add","path":"/foo/-","value":{"a":null}}]`,
+			expected: `{"foo":[1,{"a":null}]}`,
+		},
+		{
+			testName: "remove",
+			doc:      `{"foo":["bar","qux","baz"],"x":1}`,
+			patch:    `[{"op":"remove","path":"/foo/1"},{"op":"remove","path":"/x"}]`,
+			expected: `{"foo":["bar","baz"]}`,
+		},
+		{
+			testName: "replace",
+			doc:      `{"baz":"qux","foo":"bar"}`,
+			patch:    `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected: `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			testName: "move",
+			doc:      `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:    `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			testName: "move array element",
+			doc:      `{"foo":["all","grass","cows","eat"]}`,
+			patch:    `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected: `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			testName: "copy is deep",
+			doc:      `{"a":{"b":1}}`,
+			patch:    `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			expected: `{"a":{"b":1},"c":{"b":2}}`,
+		},
+		{
+			testName: "test",
+			doc:      `{"baz":"qux","foo":["a",2,"c"],"n":1.0}`,
+			patch:    `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2},{"op":"test","path":"/n","value":1}]`,
+			expected: `{"baz":"qux","foo":["a",2,"c"],"n":1}`,
+		},
+		{
+			testName: "escaped pointer",
+			doc:      `{"a/b":{"m~n":1}}`,
+			patch:    `[{"op":"replace","path":"/a~1b/m~0n","value":2}]`,
+			expected: `{"a/b":{"m~n":2}}`,
+		},
+		{
+			testName: "replace root",
+			doc:      `{"a":1}`,
+			patch:    `[{"op":"replace","path":"","value":[1,2]}]`,
+			expected: `[1,2]`,
+		},
+		{
+			testName: "large numbers keep their precision",
+			doc:      `{"id":12345678901234567890}`,
+			patch:    `[{"op":"add","path":"/copy","value":98765432109876543210}]`,
+			expected: `{"id":12345678901234567890,"copy":98765432109876543210}`,
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := Apply([]byte(test.doc), []byte(test.patch))
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.testName, err)
+			continue
+		}
+		assertJSON(t, test.testName, test.expected, actual)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		testName string
+		doc      string
+		patch    string
+		index    int
+		path     string
+		err      error
+	}{
+		{testName: "missing member", doc: `{}`, patch: `[{"op":"remove","path":"/a"}]`, path: "/a", err: ErrPathNotFound},
+		{testName: "missing parent", doc: `{}`, patch: `[{"op":"add","path":"/a/b","value":1}]`, path: "/a/b", err: ErrPathNotFound},
+		{testName: "index out of bounds", doc: `[1]`, patch: `[{"op":"add","path":"/0","value":0},{"op":"add","path":"/5","value":1}]`, index: 1, path: "/5", err: ErrInvalidIndex},
+		{testName: "leading zero", doc: `[1,2]`, patch: `[{"op":"replace","path":"/01","value":1}]`, path: "/01", err: ErrInvalidIndex},
+		{testName: "dash outside add", doc: `[1]`, patch: `[{"op":"remove","path":"/-"}]`, path: "/-", err: ErrInvalidIndex},
+		{testName: "failed test", doc: `{"a":"1"}`, patch: `[{"op":"test","path":"/a","value":1}]`, path: "/a", err: ErrTestFailed},
+		{testName: "bad from", doc: `{"a":1}`, patch: `[{"op":"move","from":"/b","path":"/c"}]`, path: "/b", err: ErrPathNotFound},
+		{testName: "move into child", doc: `{"a":{}}`, patch: `[{"op":"move","from":"/a","path":"/a/b"}]`, path: "/a", err: ErrInvalidOperation},
+		{testName: "invalid pointer", doc: `{}`, patch: `[{"op":"add","path":"a","value":1}]`, path: "a", err: ErrInvalidPointer},
+		{testName: "invalid escape", doc: `{}`, patch: `[{"op":"add","path":"/a~2","value":1}]`, path: "/a~2", err: ErrInvalidPointer},
+		{testName: "unknown op", doc: `{}`, patch: `[{"op":"test","path":"","value":{}},{"op":"upsert","path":"/a"}]`, index: 1, path: "/a", err: ErrInvalidOperation},
+		{testName: "missing value", doc: `{}`, patch: `[{"op":"add","path":"/a"}]`, path: "/a", err: ErrInvalidOperation},
+		{testName: "missing from", doc: `{}`, patch: `[{"op":"copy","path":"/a"}]`, path: "/a", err: ErrInvalidOperation},
+	}
+
+	for _, test := range tests {
+		_, err := Apply([]byte(test.doc), []byte(test.patch))
+		var patchErr *Error
+		if !errors.As(err, &patchErr) || !errors.Is(err, test.err) || patchErr.Index != test.index || patchErr.Path != test.path {
+			t.Errorf("%s failed (%s): expected %v at operation %d (%s), got %v", t.Name(), test.testName, test.err, test.index, test.path, err)
+		}
+	}
+}
+
+type user struct {
+	Name  string            `json:"name"`
+	Email string            `json:"email,omitempty"`
+	Tags  []string          `json:"tags"`
+	Meta  map[string]string `json:"meta,omitempty"`
+}
+
+func TestApplyTo(t *testing.T) {
+	u := user{Name: "alice", Email: "alice@example.com", Tags: []string{"admin"}}
+	patch := Patch{
+		{Op: "test", Path: "/name", Value: "alice"},
+		{Op: "remove", Path: "/email"},
+		{Op: "add", Path: "/tags/-", Value: "ops"},
+		{Op: "add", Path: "/meta", Value: map[string]string{"team": "core"}},
+	}
+	if err := patch.ApplyTo(&u); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	expected := user{Name: "alice", Tags: []string{"admin", "ops"}, Meta: map[string]string{"team": "core"}}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("%s failed: expected %+v, got %+v", t.Name(), expected, u)
+	}
+
+	failing := Patch{{Op: "replace", Path: "/name", Value: "bob"}, {Op: "test", Path: "/name", Value: "carol"}}
+	if err := failing.ApplyTo(&u); !errors.Is(err, ErrTestFailed) || u.Name != "alice" {
+		t.Errorf("%s failed: expected a failed test leaving the value untouched, got %v and %+v", t.Name(), err, u)
+	}
+}
+
+func TestPatchJSON(t *testing.T) {
+	patch := Patch{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "remove", Path: "/b", Value: "ignored"},
+		{Op: "copy", From: "/a", Path: "/c"},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},{"op":"copy","path":"/c","from":"/a"}]`
+	if string(data) != expected {
+		t.Errorf("%s failed: expected %s, got %s", t.Name(), expected, data)
+	}
+	decoded, err := DecodePatch(data)
+	if err != nil || len(decoded) != 3 || decoded[0].Value != nil || decoded[2].From != "/a" {
+		t.Errorf("%s failed: unexpected round trip %+v (%v)", t.Name(), decoded, err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		testName string
+		a        string
+		b        string
+	}{
+		{testName: "equal", a: `{"a":1}`, b: `{"a":1}`},
+		{testName: "members", a: `{"a":1,"b":{"c":"x"},"d":true}`, b: `{"a":2,"b":{"c":"x","e":[1]},"f":null}`},
+		{testName: "shrinking array", a: `{"items":[1,2,3,4,5]}`, b: `{"items":[1,9]}`},
+		{testName: "growing array", a: `[{"id":1}]`, b: `[{"id":1},{"id":2},{"id":3}]`},
+		{testName: "type change", a: `{"a":[1]}`, b: `{"a":{"0":1}}`},
+		{testName: "null and empty", a: `{"a":null}`, b: `{"a":[]}`},
+		{testName: "escaped keys", a: `{"a/b":1,"m~n":2}`, b: `{"a/b":3}`},
+	}
+
+	for _, test := range tests {
+		var a, b any
+		_ = json.Unmarshal([]byte(test.a), &a)
+		_ = json.Unmarshal([]byte(test.b), &b)
+		patch, err := Create(a, b)
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.testName, err)
+			continue
+		}
+		if test.testName == "equal" && len(patch) != 0 {
+			t.Errorf("%s failed (%s): expected an empty patch, got %+v", t.Name(), test.testName, patch)
+		}
+		actual, err := patch.Apply([]byte(test.a))
+		if err != nil {
+			t.Errorf("%s failed (%s): cannot apply %+v: %v", t.Name(), test.testName, patch, err)
+			continue
+		}
+		assertJSON(t, test.testName, test.b, actual)
+	}
+
+	patch, _ := Create(user{Name: "alice", Tags: []string{"a", "b"}}, user{Name: "bob", Tags: []string{"a"}})
+	expected := Patch{{Op: "replace", Path: "/name", Value: "bob"}, {Op: "remove", Path: "/tags/1"}}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("%s failed (structs): expected %+v, got %+v", t.Name(), expected, patch)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// examples from RFC 7386, appendix A
+	tests := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, expected: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, expected: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, expected: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, expected: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, expected: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, expected: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, expected: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, expected: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, expected: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, expected: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, expected: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, expected: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, expected: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, expected: `{"a":{"bb":{}}}`},
+	}
+
+	for _, test := range tests {
+		actual, err := MergePatch([]byte(test.doc), []byte(test.patch))
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.patch, err)
+			continue
+		}
+		assertJSON(t, test.patch, test.expected, actual)
+	}
+
+	u := user{Name: "alice", Email: "alice@example.com", Tags: []string{"a"}}
+	if err := MergeInto(&u, []byte(`{"email":null,"tags":["b","c"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := user{Name: "alice", Tags: []string{"b", "c"}}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("%s failed (MergeInto): expected %+v, got %+v", t.Name(), expected, u)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	a := map[string]any{"a": "b", "c": map[string]any{"d": "e", "f": "g"}, "h": []int{1}}
+	b := map[string]any{"a": "z", "c": map[string]any{"d": "e"}, "h": []int{1}, "i": 1}
+	patch, err := CreateMergePatch(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, "patch", `{"a":"z","c":{"f":null},"i":1}`, patch)
+
+	doc, _ := json.Marshal(a)
+	merged, err := MergePatch(doc, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := json.Marshal(b)
+	assertJSON(t, "round trip", string(expected), merged)
+}
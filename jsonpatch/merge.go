@@ -0,0 +1,85 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to a JSON document:
+// the members of the patch replace the ones of the document, objects are merged recursively and null removes a member
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: invalid document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: invalid merge patch: %w", err)
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// MergeInto applies a JSON Merge Patch to the value pointed by target, going through its JSON representation
+func MergeInto(target any, patch []byte) error {
+	return throughJSON(target, func(doc []byte) ([]byte, error) {
+		return MergePatch(doc, patch)
+	})
+}
+
+// CreateMergePatch returns the JSON Merge Patch which turns the JSON representation of a into the one of b.
+// Since null removes a member, a merge patch cannot set a member to null: use a JSON Patch for that.
+func CreateMergePatch(a, b any) ([]byte, error) {
+	na, err := normalize(a)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: %w", err)
+	}
+	nb, err := normalize(b)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpatch: %w", err)
+	}
+	patch, _ := mergeDiff(na, nb)
+	return json.Marshal(patch)
+}
+
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = merge(t[k], v)
+		}
+	}
+	return t
+}
+
+// mergeDiff returns the merge patch from a to b and false if they are equal
+func mergeDiff(a, b any) (any, bool) {
+	objA, okA := a.(map[string]any)
+	objB, okB := b.(map[string]any)
+	if !okA || !okB {
+		return b, !equal(a, b)
+	}
+	patch := map[string]any{}
+	for k := range objA {
+		if _, ok := objB[k]; !ok {
+			patch[k] = nil
+		}
+	}
+	for k, v := range objB {
+		old, ok := objA[k]
+		if !ok {
+			patch[k] = v
+		} else if p, changed := mergeDiff(old, v); changed {
+			patch[k] = p
+		}
+	}
+	return patch, len(patch) > 0
+}
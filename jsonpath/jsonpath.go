@@ -0,0 +1,225 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gyozatech/sushi/mapper"
+)
+
+var (
+	// ErrNotFound is returned when an expression doesn't match any value
+	ErrNotFound = errors.New("jsonpath: path not found")
+	// ErrInvalidPath is returned for the malformed JSON Pointers and JSONPath expressions
+	ErrInvalidPath = errors.New("jsonpath: invalid path")
+)
+
+// Get returns the value found at a JSON Pointer (e.g. "/items/0/name") or at a JSONPath expression
+// (e.g. "$.items[0].name"), converted to T by the mapper package if it isn't already a T.
+// When a JSONPath matches several values, the first one is returned.
+func Get[T any](root any, expr string) (*T, error) {
+	matches, err := find(root, expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	return convert[T](matches[0])
+}
+
+// GetAll returns all the values matched by a JSON Pointer or a JSONPath expression, converted to T
+func GetAll[T any](root any, expr string) ([]T, error) {
+	matches, err := find(root, expr)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(matches))
+	for _, m := range matches {
+		v, err := convert[T](m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *v)
+	}
+	return result, nil
+}
+
+// Exists returns true if the JSON Pointer or the JSONPath expression matches at least one value
+func Exists(root any, expr string) bool {
+	matches, err := find(root, expr)
+	return err == nil && len(matches) > 0
+}
+
+// Set assigns value to all the locations matched by a JSON Pointer or a JSONPath expression,
+// converting it to their type if needed. root must be a pointer or a non-nil map.
+// The missing map entries along a JSON Pointer, or a JSONPath made only of names and indexes, are created,
+// and the index "-" (or the one after the last element) appends to a slice.
+func Set(root any, expr string, value any) error {
+	target, err := settable(root)
+	if err != nil {
+		return err
+	}
+	tokens, definite, err := definiteTokens(expr)
+	if err != nil {
+		return err
+	}
+	assign := func(parent reflect.Value, token string) error {
+		return setChild(parent, token, value)
+	}
+	if definite {
+		if len(tokens) == 0 {
+			if err := replaceable(root); err != nil {
+				return err
+			}
+			return setValue(target, value)
+		}
+		return modify(target, tokens, true, assign)
+	}
+
+	matches, err := find(root, expr)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	for _, m := range matches {
+		if len(m.tokens) == 0 {
+			if err = replaceable(root); err == nil {
+				err = setValue(target, value)
+			}
+		} else {
+			err = modify(target, m.tokens, false, assign)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes all the locations matched by a JSON Pointer or a JSONPath expression:
+// map entries are deleted, slice elements are removed and struct fields are reset to their zero value.
+// root must be a pointer or a non-nil map.
+func Delete(root any, expr string) error {
+	target, err := settable(root)
+	if err != nil {
+		return err
+	}
+	matches, err := find(root, expr)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, expr)
+	}
+	// the matches are deleted backwards, so that removing a slice element doesn't shift the following matches
+	for i := len(matches) - 1; i >= 0; i-- {
+		if len(matches[i].tokens) == 0 {
+			if err := replaceable(root); err != nil {
+				return err
+			}
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+		if err := modify(target, matches[i].tokens, false, deleteChild); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// match is a value found by an expression along with the JSON Pointer tokens of its location
+type match struct {
+	tokens []string
+	value  reflect.Value
+}
+
+// find resolves a JSON Pointer or a JSONPath expression, which is recognized by its leading $
+func find(root any, expr string) ([]match, error) {
+	v := reflect.ValueOf(root)
+	if strings.HasPrefix(expr, "$") {
+		segments, err := parsePath(expr)
+		if err != nil {
+			return nil, err
+		}
+		return (&evaluator{root: v}).evaluate(segments, match{tokens: []string{}, value: v}), nil
+	}
+	tokens, err := parsePointer(expr)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		var ok bool
+		if v, ok = child(v, token); !ok {
+			return nil, nil
+		}
+	}
+	return []match{{tokens: tokens, value: v}}, nil
+}
+
+// definiteTokens returns the pointer tokens of a JSON Pointer, or of a JSONPath which can match one location at most
+func definiteTokens(expr string) ([]string, bool, error) {
+	if !strings.HasPrefix(expr, "$") {
+		tokens, err := parsePointer(expr)
+		return tokens, err == nil, err
+	}
+	segments, err := parsePath(expr)
+	if err != nil {
+		return nil, false, err
+	}
+	tokens := make([]string, 0, len(segments))
+	for _, s := range segments {
+		switch {
+		case s.recursive:
+			return nil, false, nil
+		case s.kind == nameSegment:
+			tokens = append(tokens, s.name)
+		case s.kind == indexSegment && s.index >= 0:
+			tokens = append(tokens, fmt.Sprint(s.index))
+		default:
+			return nil, false, nil
+		}
+	}
+	return tokens, true, nil
+}
+
+func convert[T any](m match) (*T, error) {
+	var result T
+	v := indirect(m.value)
+	if !v.IsValid() {
+		return &result, nil
+	}
+	if r, ok := v.Interface().(T); ok {
+		return &r, nil
+	}
+	if err := mapper.Into(v.Interface(), &result); err != nil {
+		return nil, fmt.Errorf("jsonpath: %s: %w", joinPointer(m.tokens), err)
+	}
+	return &result, nil
+}
+
+func settable(root any) (reflect.Value, error) {
+	v := reflect.ValueOf(root)
+	switch {
+	case v.Kind() == reflect.Pointer && !v.IsNil():
+		return v.Elem(), nil
+	case v.Kind() == reflect.Map && !v.IsNil():
+		// maps share their entries: changing the copy changes the original
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		return c, nil
+	}
+	return reflect.Value{}, fmt.Errorf("jsonpath: expected a non-nil pointer or map, got %T", root)
+}
+
+// replaceable fails for the map roots, which cannot be replaced as a whole since they are passed by value
+func replaceable(root any) error {
+	if reflect.ValueOf(root).Kind() == reflect.Map {
+		return fmt.Errorf("%w: a map root cannot be replaced, pass a pointer to it", ErrInvalidPath)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const storeJSON = `{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 19.95}
+	},
+	"a/b": {"m~n": 1}
+}`
+
+func store(t *testing.T) map[string]any {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(storeJSON), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestGetAll(t *testing.T) {
+	doc := store(t)
+	tests := []struct {
+		expr     string
+		expected []any
+	}{
+		{expr: "/store/bicycle/color", expected: []any{"red"}},
+		{expr: "/a~1b/m~0n", expected: []any{1.0}},
+		{expr: "/store/book/4", expected: []any{}},
+		{expr: "$.store.book[*].author", expected: []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{expr: "$..author", expected: []any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"}},
+		{expr: "$.store.*.color", expected: []any{"red"}},
+		{expr: "$['store']['bicycle'].price", expected: []any{19.95}},
+		{expr: "$..book[2].title", expected: []any{"Moby Dick"}},
+		{expr: "$..book[-1].title", expected: []any{"The Lord of the Rings"}},
+		{expr: "$.store.book[:2].price", expected: []any{8.95, 12.99}},
+		{expr: "$.store.book[-2:].price", expected: []any{8.99, 22.99}},
+		{expr: "$..book[?(@.isbn)].title", expected: []any{"Moby Dick", "The Lord of the Rings"}},
+		{expr: "$..book[?(!@.isbn)].title", expected: []any{"Sayings of the Century", "Sword of Honour"}},
+		{expr: "$.store.book[?(@.price < 10)].title", expected: []any{"Sayings of the Century", "Moby Dick"}},
+		{expr: "$.store.book[?(@.category == 'fiction' && @.price >= 20)].author", expected: []any{"J. R. R. Tolkien"}},
+		{expr: `$.store.book[?(@.author == "Nigel Rees" || @.price > 20)].price`, expected: []any{8.95, 22.99}},
+		{expr: "$.store.book[?(@.price > $.store.bicycle.price)].title", expected: []any{"The Lord of the Rings"}},
+		{expr: "$..price", expected: []any{19.95, 8.95, 12.99, 8.99, 22.99}},
+		{expr: "$.store.missing", expected: []any{}},
+	}
+
+	for _, test := range tests {
+		actual, err := GetAll[any](doc, test.expr)
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), test.expr, test.expected, actual)
+		}
+	}
+}
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type Audit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type person struct {
+	Audit
+	Name      string            `json:"name"`
+	Age       int               `json:"age"`
+	Addresses []address         `json:"addresses"`
+	Manager   *person           `json:"manager,omitempty"`
+	Labels    map[string]string `json:"labels"`
+	Secret    string            `json:"-"`
+	internal  string
+}
+
+func TestGetStruct(t *testing.T) {
+	p := person{
+		Audit:     Audit{CreatedBy: "admin"},
+		Name:      "alice",
+		Age:       30,
+		Addresses: []address{{City: "Rome"}, {City: "Milan", Zip: "20100"}},
+		Manager:   &person{Name: "bob"},
+		Labels:    map[string]string{"team": "core"},
+		Secret:    "hidden",
+	}
+
+	name, err := Get[string](p, "/manager/name")
+	if err != nil || *name != "bob" {
+		t.Errorf("%s failed (pointer): expected bob, got %v (%v)", t.Name(), name, err)
+	}
+	city, err := Get[string](&p, "$.addresses[?(@.zip)].city")
+	if err != nil || *city != "Milan" {
+		t.Errorf("%s failed (filter): expected Milan, got %v (%v)", t.Name(), city, err)
+	}
+	age, err := Get[float64](p, "$.age")
+	if err != nil || *age != 30 {
+		t.Errorf("%s failed (conversion): expected 30, got %v (%v)", t.Name(), age, err)
+	}
+	createdBy, err := Get[string](p, "/createdBy")
+	if err != nil || *createdBy != "admin" {
+		t.Errorf("%s failed (embedded): expected admin, got %v (%v)", t.Name(), createdBy, err)
+	}
+	addr, err := Get[address](map[string]any{"address": map[string]any{"City": "Milan"}}, "/address")
+	if err != nil || addr.City != "Milan" {
+		t.Errorf("%s failed (map to struct): expected Milan, got %v (%v)", t.Name(), addr, err)
+	}
+	for _, expr := range []string{"/Secret", "/internal", "/manager/manager/name", "$.labels.owner", "/addresses/0/zip"} {
+		if Exists(p, expr) {
+			t.Errorf("%s failed (%s): expected not to exist", t.Name(), expr)
+		}
+		if _, err := Get[any](p, expr); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s failed (%s): expected ErrNotFound, got %v", t.Name(), expr, err)
+		}
+	}
+	if !Exists(p, "/labels/team") || !Exists(p, "$..city") {
+		t.Errorf("%s failed: expected the paths to exist", t.Name())
+	}
+	if _, err := Get[int](p, "/name"); err == nil {
+		t.Errorf("%s failed: expected a conversion error", t.Name())
+	}
+}
+
+func TestInvalidPaths(t *testing.T) {
+	for _, expr := range []string{"store", "/a~2", "$.", "$[", "$[1", "$['a", "$[?(@.a ==)]", "$[?(1)]", "$.a b", "$[x]"} {
+		if _, err := Get[any](map[string]any{}, expr); !errors.Is(err, ErrInvalidPath) {
+			t.Errorf("%s failed (%s): expected ErrInvalidPath, got %v", t.Name(), expr, err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	doc := store(t)
+	tests := []struct {
+		expr  string
+		value any
+		check string
+	}{
+		{expr: "/store/bicycle/color", value: "blue", check: "/store/bicycle/color"},
+		{expr: "/store/book/-", value: map[string]any{"title": "New"}, check: "/store/book/4"},
+		{expr: "/store/owner/name", value: "carol", check: "/store/owner/name"},
+		{expr: "$.store.bicycle.gears", value: 21, check: "/store/bicycle/gears"},
+		{expr: "$.store.book[?(@.price > 20)].price", value: 19.99, check: "/store/book/3/price"},
+	}
+
+	for _, test := range tests {
+		if err := Set(doc, test.expr, test.value); err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.expr, err)
+			continue
+		}
+		actual, err := Get[any](doc, test.check)
+		if err != nil || !reflect.DeepEqual(*actual, test.value) {
+			t.Errorf("%s failed (%s): expected %v, got %v (%v)", t.Name(), test.expr, test.value, actual, err)
+		}
+	}
+
+	if err := Set(doc, "$..book[?(@.price > 100)].price", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("%s failed (no match): expected ErrNotFound, got %v", t.Name(), err)
+	}
+	if err := Set(doc, "/store/book/9", 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("%s failed (out of bounds): expected ErrNotFound, got %v", t.Name(), err)
+	}
+	if err := Set(*new(map[string]any), "/a", 1); err == nil {
+		t.Errorf("%s failed (nil map): expected an error", t.Name())
+	}
+}
+
+func TestSetStruct(t *testing.T) {
+	var p person
+	steps := []struct {
+		expr  string
+		value any
+	}{
+		{expr: "/name", value: "alice"},
+		{expr: "/age", value: "42"},
+		{expr: "/createdBy", value: "admin"},
+		{expr: "/addresses/-", value: map[string]any{"city": "Rome"}},
+		{expr: "$.addresses[0].zip", value: "00100"},
+		{expr: "/manager/name", value: "bob"},
+		{expr: "/labels/team", value: "core"},
+	}
+	for _, step := range steps {
+		if err := Set(&p, step.expr, step.value); err != nil {
+			t.Fatalf("%s failed (%s): unexpected error %v", t.Name(), step.expr, err)
+		}
+	}
+	expected := person{
+		Audit:     Audit{CreatedBy: "admin"},
+		Name:      "alice",
+		Age:       42,
+		Addresses: []address{{City: "Rome", Zip: "00100"}},
+		Manager:   &person{Name: "bob"},
+		Labels:    map[string]string{"team": "core"},
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("%s failed: expected %+v, got %+v", t.Name(), expected, p)
+	}
+	if err := Set(&p, "/age", "old"); err == nil {
+		t.Errorf("%s failed: expected a conversion error", t.Name())
+	}
+	if err := Set(p, "/age", 1); err == nil {
+		t.Errorf("%s failed: expected an error for a non-pointer struct", t.Name())
+	}
+
+	var o outer
+	if err := Set(&o, "/x", 1); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("%s failed: expected ErrInvalidPath through a nil pointer to an unexported struct, got %v", t.Name(), err)
+	}
+	o.inner = &inner{}
+	if err := Set(&o, "/x", 1); err != nil || o.X != 1 {
+		t.Errorf("%s failed: expected the promoted field to be set, got %+v (%v)", t.Name(), o.inner, err)
+	}
+}
+
+type inner struct {
+	X int `json:"x"`
+}
+
+type outer struct {
+	*inner
+	Y int `json:"y"`
+}
+
+func TestDelete(t *testing.T) {
+	doc := store(t)
+	if err := Delete(doc, "$.store.book[?(@.category == 'fiction')]"); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	titles, _ := GetAll[string](doc, "$..title")
+	if !reflect.DeepEqual(titles, []string{"Sayings of the Century"}) {
+		t.Errorf("%s failed (filter): expected only the reference book, got %v", t.Name(), titles)
+	}
+	if err := Delete(doc, "/store/bicycle/color"); err != nil || Exists(doc, "/store/bicycle/color") {
+		t.Errorf("%s failed (pointer): expected the color to be deleted (%v)", t.Name(), err)
+	}
+	if err := Delete(doc, "/store/bicycle/color"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("%s failed (missing): expected ErrNotFound, got %v", t.Name(), err)
+	}
+
+	p := person{Name: "alice", Addresses: []address{{City: "Rome"}, {City: "Milan"}, {City: "Turin"}}, Manager: &person{Name: "bob"}}
+	if err := Delete(&p, "$.addresses[0:2]"); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if err := Delete(&p, "/manager/name"); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	expected := person{Name: "alice", Addresses: []address{{City: "Turin"}}, Manager: &person{}}
+	if !reflect.DeepEqual(p, expected) {
+		t.Errorf("%s failed (struct): expected %+v, got %+v", t.Name(), expected, p)
+	}
+
+	m := map[string]any{"a": 1}
+	if err := Delete(m, ""); !errors.Is(err, ErrInvalidPath) || len(m) != 1 {
+		t.Errorf("%s failed (map root): expected ErrInvalidPath, got %v", t.Name(), err)
+	}
+	if err := Delete(&m, ""); err != nil || m != nil {
+		t.Errorf("%s failed (map pointer root): expected a nil map, got %v (%v)", t.Name(), m, err)
+	}
+}
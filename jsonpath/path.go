@@ -0,0 +1,518 @@
+package jsonpath
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type segmentKind int
+
+const (
+	nameSegment segmentKind = iota
+	indexSegment
+	wildcardSegment
+	sliceSegment
+	filterSegment
+)
+
+// segment is a step of a JSONPath expression
+type segment struct {
+	kind segmentKind
+	// recursive is true for the segments following "..", which apply to all the descendants
+	recursive bool
+	name      string
+	index     int
+	start     *int
+	end       *int
+	filter    *condition
+}
+
+// parsePath parses the supported JSONPath subset: $, .name, ['name'], [n] (negative from the end),
+// [start:end], * and [*], ..name, ..* and ..[...], [?(filter)]
+func parsePath(expr string) ([]segment, error) {
+	p := &parser{s: expr}
+	if !p.consume("$") {
+		return nil, p.errorf("expected $")
+	}
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	}
+	return segments, nil
+}
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %q at %d: %s", ErrInvalidPath, p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *parser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *parser) skipSpaces() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+// segments parses segments as long as they start with . or [
+func (p *parser) segments() ([]segment, error) {
+	result := []segment{}
+	for {
+		var s segment
+		var err error
+		switch {
+		case p.consume(".."):
+			if p.consume("[") {
+				s, err = p.bracket()
+			} else {
+				s, err = p.dotted()
+			}
+			s.recursive = true
+		case p.consume("."):
+			s, err = p.dotted()
+		case p.consume("["):
+			s, err = p.bracket()
+		default:
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+}
+
+func (p *parser) dotted() (segment, error) {
+	if p.consume("*") {
+		return segment{kind: wildcardSegment}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && isNameChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return segment{}, p.errorf("expected a member name")
+	}
+	return segment{kind: nameSegment, name: p.s[start:p.pos]}, nil
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 0x80 || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (p *parser) bracket() (segment, error) {
+	p.skipSpaces()
+	var s segment
+	var err error
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		s = segment{kind: wildcardSegment}
+	case c == '\'' || c == '"':
+		var name string
+		name, err = p.quoted()
+		s = segment{kind: nameSegment, name: name}
+	case c == '?':
+		p.pos++
+		var filter *condition
+		filter, err = p.or()
+		s = segment{kind: filterSegment, filter: filter}
+	default:
+		s, err = p.indexOrSlice()
+	}
+	if err != nil {
+		return segment{}, err
+	}
+	p.skipSpaces()
+	if !p.consume("]") {
+		return segment{}, p.errorf("expected ]")
+	}
+	return s, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\\' && p.pos < len(p.s):
+			b.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) indexOrSlice() (segment, error) {
+	end := strings.IndexByte(p.s[p.pos:], ']')
+	if end < 0 {
+		return segment{}, p.errorf("expected ]")
+	}
+	text := strings.TrimSpace(p.s[p.pos : p.pos+end])
+	if from, to, ok := strings.Cut(text, ":"); ok {
+		s := segment{kind: sliceSegment}
+		var err error
+		if s.start, err = p.bound(from); err != nil {
+			return segment{}, err
+		}
+		if s.end, err = p.bound(to); err != nil {
+			return segment{}, err
+		}
+		p.pos += end
+		return s, nil
+	}
+	i, err := strconv.Atoi(text)
+	if err != nil {
+		return segment{}, p.errorf("invalid index %q", text)
+	}
+	p.pos += end
+	return segment{kind: indexSegment, index: i}, nil
+}
+
+func (p *parser) bound(text string) (*int, error) {
+	if text = strings.TrimSpace(text); text == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(text)
+	if err != nil {
+		return nil, p.errorf("invalid slice bound %q", text)
+	}
+	return &i, nil
+}
+
+// condition is a node of a filter expression
+type condition struct {
+	// op is ||, &&, ! (on left), exists (on a) or a comparison operator (between a and b)
+	op          string
+	left, right *condition
+	a, b        operand
+}
+
+// operand is either a path relative to the current node (@) or to the root ($), or a literal:
+// a number (float64), a string, a boolean or null
+type operand struct {
+	path     []segment
+	isPath   bool
+	relative bool
+	literal  any
+}
+
+func (p *parser) or() (*condition, error) {
+	left, err := p.and()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+		var right *condition
+		if right, err = p.and(); err == nil {
+			left = &condition{op: "||", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) and() (*condition, error) {
+	left, err := p.unary()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			break
+		}
+		var right *condition
+		if right, err = p.unary(); err == nil {
+			left = &condition{op: "&&", left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *parser) unary() (*condition, error) {
+	p.skipSpaces()
+	if p.consume("!") {
+		c, err := p.unary()
+		return &condition{op: "!", left: c}, err
+	}
+	if p.consume("(") {
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return c, nil
+	}
+	a, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			b, err := p.operand()
+			return &condition{op: op, a: a, b: b}, err
+		}
+	}
+	if !a.isPath {
+		return nil, p.errorf("expected a comparison")
+	}
+	return &condition{op: "exists", a: a}, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	p.skipSpaces()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.segments()
+		return operand{path: segments, isPath: true, relative: c == '@'}, err
+	case c == '\'' || c == '"':
+		s, err := p.quoted()
+		return operand{literal: s}, err
+	case p.consume("true"):
+		return operand{literal: true}, nil
+	case p.consume("false"):
+		return operand{literal: false}, nil
+	case p.consume("null"):
+		return operand{literal: nil}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte("+-.0123456789eE", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil {
+		return operand{}, p.errorf("expected a path or a literal")
+	}
+	return operand{literal: n}, nil
+}
+
+type evaluator struct {
+	root reflect.Value
+}
+
+func (e *evaluator) evaluate(segments []segment, from match) []match {
+	current := []match{from}
+	for _, s := range segments {
+		next := []match{}
+		for _, m := range current {
+			if s.recursive {
+				e.descend(m, func(d match) {
+					next = append(next, e.selectChildren(s, d)...)
+				})
+			} else {
+				next = append(next, e.selectChildren(s, m)...)
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// descend calls fn on m and on all its descendants, parents first
+func (e *evaluator) descend(m match, fn func(match)) {
+	fn(m)
+	for _, c := range children(m.value) {
+		e.descend(extend(m, c), fn)
+	}
+}
+
+func extend(parent match, c match) match {
+	tokens := make([]string, 0, len(parent.tokens)+1)
+	tokens = append(append(tokens, parent.tokens...), c.tokens...)
+	return match{tokens: tokens, value: c.value}
+}
+
+func (e *evaluator) selectChildren(s segment, m match) []match {
+	v := indirect(m.value)
+	if !v.IsValid() {
+		return nil
+	}
+	isList := v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	result := []match{}
+	switch s.kind {
+	case nameSegment:
+		if isList {
+			break
+		}
+		if c, ok := child(v, s.name); ok {
+			result = append(result, extend(m, match{tokens: []string{s.name}, value: c}))
+		}
+	case indexSegment:
+		if !isList {
+			break
+		}
+		i := s.index
+		if i < 0 {
+			i += v.Len()
+		}
+		if i >= 0 && i < v.Len() {
+			result = append(result, extend(m, match{tokens: []string{strconv.Itoa(i)}, value: v.Index(i)}))
+		}
+	case sliceSegment:
+		if !isList {
+			break
+		}
+		start, end := clamp(s.start, 0, v.Len()), clamp(s.end, v.Len(), v.Len())
+		for i := start; i < end; i++ {
+			result = append(result, extend(m, match{tokens: []string{strconv.Itoa(i)}, value: v.Index(i)}))
+		}
+	case wildcardSegment:
+		for _, c := range children(v) {
+			result = append(result, extend(m, c))
+		}
+	case filterSegment:
+		for _, c := range children(v) {
+			if e.test(s.filter, c.value) {
+				result = append(result, extend(m, c))
+			}
+		}
+	}
+	return result
+}
+
+// clamp resolves an optional slice bound, negative ones counting from the end
+func clamp(bound *int, fallback, length int) int {
+	if bound == nil {
+		return fallback
+	}
+	i := *bound
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+func (e *evaluator) test(c *condition, node reflect.Value) bool {
+	switch c.op {
+	case "||":
+		return e.test(c.left, node) || e.test(c.right, node)
+	case "&&":
+		return e.test(c.left, node) && e.test(c.right, node)
+	case "!":
+		return !e.test(c.left, node)
+	case "exists":
+		_, ok := e.value(c.a, node)
+		return ok
+	}
+	a, okA := e.value(c.a, node)
+	b, okB := e.value(c.b, node)
+	if !okA || !okB {
+		return (c.op == "==" && okA == okB) || (c.op == "!=" && okA != okB)
+	}
+	return compare(c.op, a, b)
+}
+
+// value evaluates an operand, returning false if its path doesn't match any value
+func (e *evaluator) value(o operand, node reflect.Value) (any, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+	from := e.root
+	if o.relative {
+		from = node
+	}
+	matches := e.evaluate(o.path, match{value: from})
+	if len(matches) == 0 {
+		return nil, false
+	}
+	return scalar(matches[0].value), true
+}
+
+// scalar converts a value into the representation of the filter literals: numbers become float64
+func scalar(v reflect.Value) any {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		// json.Number is compared as a number
+		if v.Type().PkgPath() == "encoding/json" && v.Type().Name() == "Number" {
+			if n, err := strconv.ParseFloat(v.String(), 64); err == nil {
+				return n
+			}
+		}
+		return v.String()
+	}
+	return v.Interface()
+}
+
+func compare(op string, a, b any) bool {
+	c, ordered := 0, false
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			ordered = true
+			if x < y {
+				c = -1
+			} else if x > y {
+				c = 1
+			}
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			c, ordered = strings.Compare(x, y), true
+		}
+	}
+	switch op {
+	case "==":
+		return (ordered && c == 0) || (!ordered && reflect.DeepEqual(a, b))
+	case "!=":
+		return !((ordered && c == 0) || (!ordered && reflect.DeepEqual(a, b)))
+	case "<":
+		return ordered && c < 0
+	case "<=":
+		return ordered && c <= 0
+	case ">":
+		return ordered && c > 0
+	case ">=":
+		return ordered && c >= 0
+	}
+	return false
+}
@@ -0,0 +1,428 @@
+package jsonpath
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gyozatech/sushi/mapper"
+	"github.com/gyozatech/sushi/utils"
+)
+
+// parsePointer splits a JSON Pointer into its reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	tokens, err := utils.ParsePointer(pointer)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPath, err)
+	}
+	return tokens, nil
+}
+
+func joinPointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// index parses an array index: the length itself is accepted only when appending, also as "-"
+func index(token string, length int, appending bool) (int, error) {
+	if token == "-" && appending {
+		return length, nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, fmt.Errorf("%w: invalid index %q", ErrInvalidPath, token)
+	}
+	if i > length || (i == length && !appending) {
+		return 0, fmt.Errorf("%w: index %d out of bounds", ErrNotFound, i)
+	}
+	return i, nil
+}
+
+// field is a struct field identified by its JSON name
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields caches the fields of the struct types
+var structFields sync.Map // map[reflect.Type]*fieldSet
+
+// fieldSet holds the fields of a struct type, also by name
+type fieldSet struct {
+	list   []field
+	byName map[string]field
+}
+
+// fields returns the cached fields of a struct
+func fields(t reflect.Type) []field {
+	return cachedFields(t).list
+}
+
+func cachedFields(t reflect.Type) *fieldSet {
+	if cached, ok := structFields.Load(t); ok {
+		return cached.(*fieldSet)
+	}
+	set := &fieldSet{list: collectFields(t), byName: map[string]field{}}
+	for _, f := range set.list {
+		if _, ok := set.byName[f.name]; !ok {
+			set.byName[f.name] = f
+		}
+	}
+	cached, _ := structFields.LoadOrStore(t, set)
+	return cached.(*fieldSet)
+}
+
+// collectFields lists the fields of a struct as encoding/json sees them, promoting the ones of the embedded structs
+func collectFields(t reflect.Type) []field {
+	result := []field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		embedded := sf.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if sf.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			for _, f := range collectFields(embedded) {
+				result = append(result, field{name: f.name, index: append([]int{i}, f.index...), omitEmpty: f.omitEmpty})
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		result = append(result, field{name: name, index: []int{i}, omitEmpty: strings.Contains(","+options+",", ",omitempty,")})
+	}
+	return result
+}
+
+func fieldByName(t reflect.Type, name string) (field, bool) {
+	f, ok := cachedFields(t).byName[name]
+	return f, ok
+}
+
+// readField returns a struct field for reading: like in its JSON representation,
+// the empty fields tagged with omitempty are missing
+func readField(v reflect.Value, f field) (reflect.Value, bool) {
+	c, err := v.FieldByIndexErr(f.index)
+	if err != nil || (f.omitEmpty && isEmpty(c)) {
+		return reflect.Value{}, false
+	}
+	return c, true
+}
+
+// isEmpty tells the values omitted by encoding/json for omitempty
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+func mapKey(m reflect.Value, token string) (reflect.Value, bool) {
+	if m.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, false
+	}
+	return reflect.ValueOf(token).Convert(m.Type().Key()), true
+}
+
+// child returns the member or the element of v identified by a pointer token
+func child(v reflect.Value, token string) (reflect.Value, bool) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return reflect.Value{}, false
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if key, ok := mapKey(v, token); ok {
+			c := v.MapIndex(key)
+			return c, c.IsValid()
+		}
+	case reflect.Slice, reflect.Array:
+		if i, err := index(token, v.Len(), false); err == nil {
+			return v.Index(i), true
+		}
+	case reflect.Struct:
+		if f, ok := fieldByName(v.Type(), token); ok {
+			return readField(v, f)
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// children lists the members of v (maps sorted by key) or its elements, along with their pointer tokens
+func children(v reflect.Value) []match {
+	v = indirect(v)
+	if !v.IsValid() {
+		return nil
+	}
+	result := []match{}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, key := range keys {
+			result = append(result, match{tokens: []string{key.String()}, value: v.MapIndex(key)})
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			result = append(result, match{tokens: []string{strconv.Itoa(i)}, value: v.Index(i)})
+		}
+	case reflect.Struct:
+		for _, f := range fields(v.Type()) {
+			if c, ok := readField(v, f); ok {
+				result = append(result, match{tokens: []string{f.name}, value: c})
+			}
+		}
+	}
+	return result
+}
+
+// modify walks the pointer tokens from v, which must be settable, and calls change on the parent of the last token.
+// When create is true the missing maps, pointers and map entries along the way are created.
+func modify(v reflect.Value, tokens []string, create bool, change func(parent reflect.Value, token string) error) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			if !create || v.NumMethod() > 0 {
+				return fmt.Errorf("%w: %s", ErrNotFound, joinPointer(tokens))
+			}
+			v.Set(reflect.ValueOf(map[string]any{}))
+		}
+		// the value held by an interface isn't settable: it's changed on a copy and then stored back
+		c := reflect.New(v.Elem().Type()).Elem()
+		c.Set(v.Elem())
+		if err := modify(c, tokens, create, change); err != nil {
+			return err
+		}
+		v.Set(c)
+		return nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			if !create {
+				return fmt.Errorf("%w: %s", ErrNotFound, joinPointer(tokens))
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return modify(v.Elem(), tokens, create, change)
+	}
+	if len(tokens) == 1 {
+		return change(v, tokens[0])
+	}
+
+	token := tokens[0]
+	switch v.Kind() {
+	case reflect.Map:
+		key, ok := mapKey(v, token)
+		if !ok {
+			break
+		}
+		if v.IsNil() {
+			if !create {
+				break
+			}
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		c := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			c.Set(existing)
+		} else if create {
+			c.Set(newContainer(c.Type()))
+		} else {
+			break
+		}
+		if err := modify(c, tokens[1:], create, change); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, c)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := index(token, v.Len(), create && v.Kind() == reflect.Slice)
+		if err != nil {
+			return err
+		}
+		if i == v.Len() {
+			v.Set(reflect.Append(v, newContainer(v.Type().Elem())))
+		}
+		return modify(v.Index(i), tokens[1:], create, change)
+	case reflect.Struct:
+		if f, ok := fieldByName(v.Type(), token); ok {
+			c, err := fieldForUpdate(v, f, create)
+			if err != nil {
+				return err
+			}
+			return modify(c, tokens[1:], create, change)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotFound, joinPointer(tokens))
+}
+
+// fieldForUpdate returns a struct field, allocating the nil embedded pointers on its way when create is true:
+// like encoding/json, it fails if one of them points to an unexported struct
+func fieldForUpdate(v reflect.Value, f field, create bool) (reflect.Value, error) {
+	for i, x := range f.index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !create {
+					return reflect.Zero(reflect.PointerTo(v.Type().Elem())), nil
+				}
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("%w: cannot set the embedded pointer to unexported struct %s", ErrInvalidPath, v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// newContainer returns the value created for a missing location along the path
+func newContainer(t reflect.Type) reflect.Value {
+	switch t.Kind() {
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return reflect.ValueOf(map[string]any{})
+		}
+	case reflect.Map:
+		return reflect.MakeMap(t)
+	case reflect.Pointer:
+		return reflect.New(t.Elem())
+	}
+	return reflect.Zero(t)
+}
+
+func setChild(parent reflect.Value, token string, value any) error {
+	switch parent.Kind() {
+	case reflect.Map:
+		key, ok := mapKey(parent, token)
+		if !ok {
+			break
+		}
+		converted, err := convertValue(value, parent.Type().Elem())
+		if err != nil {
+			return err
+		}
+		if parent.IsNil() {
+			parent.Set(reflect.MakeMap(parent.Type()))
+		}
+		parent.SetMapIndex(key, converted)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := index(token, parent.Len(), parent.Kind() == reflect.Slice)
+		if err != nil {
+			return err
+		}
+		converted, err := convertValue(value, parent.Type().Elem())
+		if err != nil {
+			return err
+		}
+		if i == parent.Len() {
+			parent.Set(reflect.Append(parent, converted))
+		} else {
+			parent.Index(i).Set(converted)
+		}
+		return nil
+	case reflect.Struct:
+		if f, ok := fieldByName(parent.Type(), token); ok {
+			target, err := fieldForUpdate(parent, f, true)
+			if err != nil {
+				return err
+			}
+			return setValue(target, value)
+		}
+	}
+	return fmt.Errorf("%w: cannot set %q in a %s", ErrNotFound, token, parent.Type())
+}
+
+func deleteChild(parent reflect.Value, token string) error {
+	switch parent.Kind() {
+	case reflect.Map:
+		if key, ok := mapKey(parent, token); ok && parent.MapIndex(key).IsValid() {
+			parent.SetMapIndex(key, reflect.Value{})
+			return nil
+		}
+	case reflect.Slice:
+		i, err := index(token, parent.Len(), false)
+		if err != nil {
+			return err
+		}
+		parent.Set(reflect.AppendSlice(parent.Slice(0, i), parent.Slice(i+1, parent.Len())))
+		return nil
+	case reflect.Array:
+		i, err := index(token, parent.Len(), false)
+		if err != nil {
+			return err
+		}
+		parent.Index(i).Set(reflect.Zero(parent.Type().Elem()))
+		return nil
+	case reflect.Struct:
+		if f, ok := fieldByName(parent.Type(), token); ok {
+			if v, _ := fieldForUpdate(parent, f, false); v.CanSet() {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNotFound, token)
+}
+
+func setValue(target reflect.Value, value any) error {
+	converted, err := convertValue(value, target.Type())
+	if err != nil {
+		return err
+	}
+	target.Set(converted)
+	return nil
+}
+
+// convertValue converts a value to the given type, through the mapper package if it isn't assignable
+func convertValue(value any, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(t), nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	result := reflect.New(t)
+	if err := mapper.Into(value, result.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("jsonpath: %w", err)
+	}
+	return result.Elem(), nil
+}
@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// the supported signature algorithms (RFC 7518 and RFC 8037)
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+// publicKey returns the public key of the private keys, so that a key set can hold the keys used to sign too
+func publicKey(key any) any {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	}
+	return key
+}
+
+// verifySignature checks the signature with a key of the type required by the algorithm,
+// so that a public key can never be used as an HMAC secret
+func verifySignature(alg string, key any, signingInput, signature []byte) error {
+	hash := sha256.Sum256(signingInput)
+	valid := false
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok || len(secret) == 0 {
+			return fmt.Errorf("%w: %s requires a []byte secret, got %T", ErrKeyNotFound, alg, key)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signingInput)
+		valid = hmac.Equal(signature, mac.Sum(nil))
+	case RS256:
+		public, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: %s requires an RSA key, got %T", ErrKeyNotFound, alg, key)
+		}
+		valid = rsa.VerifyPKCS1v15(public, crypto.SHA256, hash[:], signature) == nil
+	case ES256:
+		public, ok := publicKey(key).(*ecdsa.PublicKey)
+		if !ok || public.Curve != elliptic.P256() {
+			return fmt.Errorf("%w: %s requires a P-256 ECDSA key, got %T", ErrKeyNotFound, alg, key)
+		}
+		// the signature is the concatenation of r and s, 32 bytes each
+		if len(signature) == 64 {
+			r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+			valid = ecdsa.Verify(public, hash[:], r, s)
+		}
+	case EdDSA:
+		public, ok := publicKey(key).(ed25519.PublicKey)
+		if !ok || len(public) != ed25519.PublicKeySize {
+			return fmt.Errorf("%w: %s requires an Ed25519 key, got %T", ErrKeyNotFound, alg, key)
+		}
+		valid = ed25519.Verify(public, signingInput, signature)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign signs the signing input with a private key of the type required by the algorithm
+func sign(alg string, key any, signingInput []byte) ([]byte, error) {
+	if err := checkSigningKey(alg, key); err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(signingInput)
+	switch alg {
+	case HS256:
+		mac := hmac.New(sha256.New, key.([]byte))
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	case RS256:
+		return rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, hash[:])
+	case ES256:
+		r, s, err := ecdsa.Sign(rand.Reader, key.(*ecdsa.PrivateKey), hash[:])
+		if err != nil {
+			return nil, err
+		}
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	}
+	return ed25519.Sign(key.(ed25519.PrivateKey), signingInput), nil
+}
+
+// checkSigningKey checks that the key can sign with the algorithm: the HMAC secrets must have at least 256 bits
+// and the RSA keys at least 2048 bits (RFC 7518, section 3)
+func checkSigningKey(alg string, key any) error {
+	valid := false
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		valid = ok && len(secret) >= 32
+	case RS256:
+		private, ok := key.(*rsa.PrivateKey)
+		valid = ok && private.N.BitLen() >= 2048
+	case ES256:
+		private, ok := key.(*ecdsa.PrivateKey)
+		valid = ok && private.Curve == elliptic.P256()
+	case EdDSA:
+		private, ok := key.(ed25519.PrivateKey)
+		valid = ok && len(private) == ed25519.PrivateKeySize
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, alg)
+	}
+	if !valid {
+		return fmt.Errorf("jwt: invalid %s signing key %T", alg, key)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gyozatech/sushi/ids"
+)
+
+// Claims are the registered claims plus the custom ones of type T, which are encoded in the same JSON object:
+// T must be encoded as an object whose keys don't clash with the registered claims
+type Claims[T any] struct {
+	RegisteredClaims
+	Custom T
+}
+
+// MarshalJSON merges the registered and the custom claims
+func (c Claims[T]) MarshalJSON() ([]byte, error) {
+	registered, err := toObject(c.RegisteredClaims)
+	if err != nil {
+		return nil, err
+	}
+	custom, err := toObject(c.Custom)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range custom {
+		if _, ok := registered[name]; ok {
+			return nil, fmt.Errorf("jwt: the custom claim %q clashes with a registered claim", name)
+		}
+		registered[name] = value
+	}
+	return json.Marshal(registered)
+}
+
+// UnmarshalJSON decodes both the registered and the custom claims from the object
+func (c *Claims[T]) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.RegisteredClaims); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.Custom)
+}
+
+func toObject(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("jwt: the claims must be encoded as a JSON object: %w", err)
+	}
+	return object, nil
+}
+
+// ClaimsBuilder builds Claims with custom claims of type T
+type ClaimsBuilder[T any] struct {
+	claims Claims[T]
+}
+
+// NewClaims starts building claims issued now, with a random ID (jti)
+func NewClaims[T any](custom T) *ClaimsBuilder[T] {
+	b := &ClaimsBuilder[T]{claims: Claims[T]{Custom: custom}}
+	b.claims.IssuedAt = NewNumericDate(now())
+	b.claims.ID = ids.NewV4().String()
+	return b
+}
+
+// Issuer sets the iss claim
+func (b *ClaimsBuilder[T]) Issuer(issuer string) *ClaimsBuilder[T] {
+	b.claims.Issuer = issuer
+	return b
+}
+
+// Subject sets the sub claim
+func (b *ClaimsBuilder[T]) Subject(subject string) *ClaimsBuilder[T] {
+	b.claims.Subject = subject
+	return b
+}
+
+// Audience sets the aud claim
+func (b *ClaimsBuilder[T]) Audience(audience ...string) *ClaimsBuilder[T] {
+	b.claims.Audience = audience
+	return b
+}
+
+// ExpiresIn sets the exp claim to the given time after the issue time
+func (b *ClaimsBuilder[T]) ExpiresIn(d time.Duration) *ClaimsBuilder[T] {
+	b.claims.ExpiresAt = NewNumericDate(b.claims.IssuedAt.Add(d))
+	return b
+}
+
+// ExpiresAt sets the exp claim
+func (b *ClaimsBuilder[T]) ExpiresAt(t time.Time) *ClaimsBuilder[T] {
+	b.claims.ExpiresAt = NewNumericDate(t)
+	return b
+}
+
+// NotBefore sets the nbf claim
+func (b *ClaimsBuilder[T]) NotBefore(t time.Time) *ClaimsBuilder[T] {
+	b.claims.NotBefore = NewNumericDate(t)
+	return b
+}
+
+// ID replaces the random jti claim
+func (b *ClaimsBuilder[T]) ID(id string) *ClaimsBuilder[T] {
+	b.claims.ID = id
+	return b
+}
+
+// Build returns the claims
+func (b *ClaimsBuilder[T]) Build() Claims[T] {
+	return b.claims
+}
@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned for the tokens which aren't well-formed JWS compact serializations
+	ErrMalformed = errors.New("jwt: malformed token")
+	// ErrUnsupportedAlgorithm is returned for the tokens signed with an algorithm which isn't supported or allowed
+	ErrUnsupportedAlgorithm = errors.New("jwt: unsupported algorithm")
+	// ErrKeyNotFound is returned when the key set has no key for the token
+	ErrKeyNotFound = errors.New("jwt: key not found")
+	// ErrKeysUnavailable is returned when the keys cannot be loaded, e.g. the JWKS endpoint is unreachable
+	ErrKeysUnavailable = errors.New("jwt: keys unavailable")
+	// ErrInvalidSignature is returned for the tokens whose signature doesn't match
+	ErrInvalidSignature = errors.New("jwt: invalid signature")
+	// ErrExpired is returned for the tokens whose exp claim is in the past
+	ErrExpired = errors.New("jwt: token expired")
+	// ErrNotYetValid is returned for the tokens whose nbf or iat claims are in the future
+	ErrNotYetValid = errors.New("jwt: token not valid yet")
+	// ErrInvalidIssuer is returned for the tokens whose iss claim isn't the expected one
+	ErrInvalidIssuer = errors.New("jwt: invalid issuer")
+	// ErrInvalidAudience is returned for the tokens whose aud claim doesn't contain the expected audience
+	ErrInvalidAudience = errors.New("jwt: invalid audience")
+)
+
+// now is the clock used to validate the time claims, replaced by the tests
+var now = time.Now
+
+// Header is the JOSE header of a token
+type Header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+}
+
+// NumericDate is a time encoded as the number of seconds since the Unix epoch
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate returns the NumericDate of a time, truncated to the second
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON encodes the date as an integer number of seconds
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprint(d.Unix())), nil
+}
+
+// UnmarshalJSON decodes a number of seconds, possibly with a fractional part
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("%w: invalid numeric date %s", ErrMalformed, data)
+	}
+	// the milliseconds must fit an int64: the conversion of larger values is undefined
+	millis := seconds * 1000
+	if millis >= math.MaxInt64 || millis < math.MinInt64 {
+		return fmt.Errorf("%w: numeric date %s out of range", ErrMalformed, data)
+	}
+	d.Time = time.UnixMilli(int64(millis))
+	return nil
+}
+
+// Audience is the aud claim: a single string or an array of strings
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decodes a string or an array of strings
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("%w: invalid audience %s", ErrMalformed, data)
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains tells whether the audience includes the given one
+func (a Audience) Contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisteredClaims are the claims defined by RFC 7519: they can be embedded in the custom claims types
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Parse verifies the signature and the registered claims of a token and decodes its claims into T,
+// which usually embeds RegisteredClaims
+func Parse[T any](ctx context.Context, v *Verifier, token string) (*T, error) {
+	payload, err := v.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	claims := new(T)
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("%w: cannot decode the claims: %v", ErrMalformed, err)
+	}
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url segment without padding
+func decodeSegment(segment string) ([]byte, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64url segment", ErrMalformed)
+	}
+	return decoded, nil
+}
+
+// split splits a token into its header, payload and signature, returning the signing input too
+func split(token string) (header Header, payload, signature []byte, signingInput string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Header{}, nil, nil, "", fmt.Errorf("%w: expected 3 segments, got %d", ErrMalformed, len(parts))
+	}
+	rawHeader, err := decodeSegment(parts[0])
+	if err != nil {
+		return Header{}, nil, nil, "", err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawHeader, &fields); err != nil {
+		return Header{}, nil, nil, "", fmt.Errorf("%w: invalid header", ErrMalformed)
+	}
+	if _, ok := fields["crit"]; ok {
+		// no critical extension is supported (RFC 7515, section 4.1.11)
+		return Header{}, nil, nil, "", fmt.Errorf("%w: unsupported critical header parameters", ErrMalformed)
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil || header.Algorithm == "" {
+		return Header{}, nil, nil, "", fmt.Errorf("%w: invalid header", ErrMalformed)
+	}
+	if payload, err = decodeSegment(parts[1]); err != nil {
+		return Header{}, nil, nil, "", err
+	}
+	if !json.Valid(payload) || !bytes.HasPrefix(bytes.TrimSpace(payload), []byte("{")) {
+		return Header{}, nil, nil, "", fmt.Errorf("%w: the payload isn't a JSON object", ErrMalformed)
+	}
+	if signature, err = decodeSegment(parts[2]); err != nil {
+		return Header{}, nil, nil, "", err
+	}
+	return header, payload, signature, parts[0] + "." + parts[1], nil
+}
@@ -0,0 +1,184 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	hmacSecret    = []byte("0123456789abcdef0123456789abcdef")
+	rsaKey, _     = rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaKey, _   = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, ed25519Key = mustEd25519()
+)
+
+func mustEd25519() (ed25519.PublicKey, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return public, private
+}
+
+// fixClock makes the verifier read the given time, restoring the real clock at the end of the test
+func fixClock(t *testing.T, at time.Time) {
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = time.Now })
+}
+
+// signToken returns a token with any header and claims signed with the key
+func signToken(t *testing.T, header map[string]any, claims any, key any) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("cannot encode %v: %v", v, err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(header) + "." + encode(claims)
+	hash := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		signature, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, hash[:])
+	case *ecdsa.PrivateKey:
+		r, s, _ := ecdsa.Sign(rand.Reader, k, hash[:])
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signingInput))
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// tamper replaces the payload of the token
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"mallory"}`))
+	return strings.Join(parts, ".")
+}
+
+type customClaims struct {
+	RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+func TestParseAlgorithms(t *testing.T) {
+	tests := []struct {
+		alg        string
+		signingKey any
+		verifyKey  any
+	}{
+		{HS256, hmacSecret, hmacSecret},
+		{RS256, rsaKey, &rsaKey.PublicKey},
+		{ES256, ecdsaKey, &ecdsaKey.PublicKey},
+		{EdDSA, ed25519Key, ed25519Key.Public()},
+		{RS256, rsaKey, rsaKey},
+	}
+	for _, test := range tests {
+		token := signToken(t, map[string]any{"alg": test.alg, "typ": "JWT", "kid": "key-1"}, map[string]any{"sub": "alice", "roles": []string{"admin"}}, test.signingKey)
+		claims, err := Parse[customClaims](context.Background(), NewVerifier(StaticKeys{"key-1": test.verifyKey}), token)
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.alg, err)
+			continue
+		}
+		if claims.Subject != "alice" || len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+			t.Errorf("%s failed (%s): unexpected claims %+v", t.Name(), test.alg, claims)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	valid := signToken(t, map[string]any{"alg": HS256}, map[string]any{"sub": "alice"}, hmacSecret)
+	keys := StaticKeys{"": hmacSecret}
+
+	tests := []struct {
+		testName string
+		token    string
+		verifier *Verifier
+		expected error
+	}{
+		{"wrong secret", signToken(t, map[string]any{"alg": HS256}, map[string]any{}, []byte("another secret")), NewVerifier(keys), ErrInvalidSignature},
+		{"tampered payload", tamper(valid), NewVerifier(keys), ErrInvalidSignature},
+		{"alg none", signToken(t, map[string]any{"alg": "none"}, map[string]any{}, nil), NewVerifier(keys), ErrUnsupportedAlgorithm},
+		{"algorithm not allowed", valid, NewVerifier(keys, WithAlgorithms(RS256)), ErrUnsupportedAlgorithm},
+		{"public key as HMAC secret", valid, NewVerifier(StaticKeys{"": &rsaKey.PublicKey}), ErrKeyNotFound},
+		{"unknown kid", signToken(t, map[string]any{"alg": HS256, "kid": "other"}, map[string]any{}, hmacSecret), NewVerifier(StaticKeys{"key-1": hmacSecret}), ErrKeyNotFound},
+		{"two segments", "eyJhbGciOiJIUzI1NiJ9.e30", NewVerifier(keys), ErrMalformed},
+		{"invalid base64", "eyJhbGciOiJIUzI1NiJ9.e30=.AAAA", NewVerifier(keys), ErrMalformed},
+		{"critical header", signToken(t, map[string]any{"alg": HS256, "crit": []string{"exp"}}, map[string]any{}, hmacSecret), NewVerifier(keys), ErrMalformed},
+		{"payload not an object", signToken(t, map[string]any{"alg": HS256}, []string{"alice"}, hmacSecret), NewVerifier(keys), ErrMalformed},
+		{"invalid registered claim", signToken(t, map[string]any{"alg": HS256}, map[string]any{"exp": "tomorrow"}, hmacSecret), NewVerifier(keys), ErrMalformed},
+		{"huge not before", signToken(t, map[string]any{"alg": HS256}, map[string]any{"nbf": 1e30}, hmacSecret), NewVerifier(keys), ErrMalformed},
+		{"huge negative expiration", signToken(t, map[string]any{"alg": HS256}, map[string]any{"exp": -1e30}, hmacSecret), NewVerifier(keys), ErrMalformed},
+	}
+	for _, test := range tests {
+		if _, err := Parse[RegisteredClaims](context.Background(), test.verifier, test.token); !errors.Is(err, test.expected) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), test.testName, test.expected, err)
+		}
+	}
+}
+
+func TestParseClaims(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, at)
+	keys := StaticKeys{"": hmacSecret}
+	unix := func(d time.Duration) int64 { return at.Add(d).Unix() }
+
+	tests := []struct {
+		testName string
+		claims   map[string]any
+		options  []VerifierOption
+		expected error
+	}{
+		{"valid", map[string]any{"exp": unix(time.Minute), "nbf": unix(-time.Minute), "iat": unix(-time.Minute)}, nil, nil},
+		{"expired", map[string]any{"exp": unix(0)}, nil, ErrExpired},
+		{"expired within skew", map[string]any{"exp": unix(-20 * time.Second)}, []VerifierOption{WithClockSkew(30 * time.Second)}, nil},
+		{"not yet valid", map[string]any{"nbf": unix(time.Minute)}, nil, ErrNotYetValid},
+		{"not yet valid within skew", map[string]any{"nbf": unix(20 * time.Second)}, []VerifierOption{WithClockSkew(30 * time.Second)}, nil},
+		{"issued in the future", map[string]any{"iat": unix(time.Minute)}, nil, ErrNotYetValid},
+		{"fractional dates", map[string]any{"exp": float64(unix(0)) + 0.5}, nil, nil},
+		{"expected issuer", map[string]any{"iss": "https://issuer"}, []VerifierOption{WithIssuer("https://issuer")}, nil},
+		{"wrong issuer", map[string]any{"iss": "https://other"}, []VerifierOption{WithIssuer("https://issuer")}, ErrInvalidIssuer},
+		{"missing issuer", map[string]any{}, []VerifierOption{WithIssuer("https://issuer")}, ErrInvalidIssuer},
+		{"audience string", map[string]any{"aud": "api"}, []VerifierOption{WithAudience("api")}, nil},
+		{"audience array", map[string]any{"aud": []string{"web", "api"}}, []VerifierOption{WithAudience("api")}, nil},
+		{"wrong audience", map[string]any{"aud": []string{"web"}}, []VerifierOption{WithAudience("api")}, ErrInvalidAudience},
+	}
+	for _, test := range tests {
+		token := signToken(t, map[string]any{"alg": HS256}, test.claims, hmacSecret)
+		if _, err := Parse[RegisteredClaims](context.Background(), NewVerifier(keys, test.options...), token); !errors.Is(err, test.expected) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), test.testName, test.expected, err)
+		}
+	}
+}
+
+func TestRegisteredClaimsJSON(t *testing.T) {
+	claims := RegisteredClaims{Subject: "alice", Audience: Audience{"api"}, ExpiresAt: NewNumericDate(time.Unix(1714557600, 500))}
+	data, err := json.Marshal(claims)
+	if err != nil || string(data) != `{"sub":"alice","aud":"api","exp":1714557600}` {
+		t.Errorf("%s failed: unexpected JSON %s (%v)", t.Name(), data, err)
+	}
+	var decoded RegisteredClaims
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.ExpiresAt.Equal(time.Unix(1714557600, 0)) || decoded.Audience[0] != "api" {
+		t.Errorf("%s failed: unexpected claims %+v (%v)", t.Name(), decoded, err)
+	}
+}
@@ -0,0 +1,290 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeySet provides the keys verifying the tokens: []byte secrets for HS256, *rsa.PublicKey for RS256,
+// *ecdsa.PublicKey for ES256 and ed25519.PublicKey for EdDSA (or the corresponding private keys)
+type KeySet interface {
+	// Key returns the key with the given ID (empty if the token has no kid header) for the algorithm
+	Key(ctx context.Context, kid, alg string) (any, error)
+}
+
+// StaticKeys is a KeySet of keys by ID: the tokens without a kid header are verified with the only key of the set
+type StaticKeys map[string]any
+
+// Key returns the key with the given ID
+func (s StaticKeys) Key(ctx context.Context, kid, alg string) (any, error) {
+	if key, ok := s[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(s) == 1 {
+		for _, key := range s {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+}
+
+// DefaultJWKSTimeout is the timeout of the default client fetching the JWKS documents
+const DefaultJWKSTimeout = 10 * time.Second
+
+// JWKSOption customizes a JWKS
+type JWKSOption func(*JWKS)
+
+// WithHTTPClient sets the client fetching the JWKS document (by default a client with a DefaultJWKSTimeout timeout):
+// it should have a timeout, since the verifications needing a refresh wait for it
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(j *JWKS) {
+		j.client = client
+	}
+}
+
+// WithRefreshInterval sets how long the keys are cached (1 hour by default)
+func WithRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.refreshInterval = interval
+	}
+}
+
+// WithMinRefreshInterval sets the minimum time between two fetches (1 minute by default),
+// so that tokens with random key IDs or an unreachable endpoint don't cause a fetch per token
+func WithMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.minRefreshInterval = interval
+	}
+}
+
+// JWKS is a KeySet caching the keys of a JSON Web Key Set document (RFC 7517) fetched from a URL.
+// The keys are refreshed when the cache expires or a token has an unknown key ID: if the refresh fails, the cached keys are kept.
+// The keys found in the cache never wait for a refresh, and the concurrent refreshes share the same fetch.
+// It's safe for concurrent use.
+type JWKS struct {
+	url                string
+	client             *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+
+	mu sync.Mutex
+	// keys is replaced as a whole by a refresh, and never modified
+	keys        map[string]jwk
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// err is the error of the last refresh
+	err error
+	// refreshing is closed when the refresh in progress completes
+	refreshing chan struct{}
+}
+
+// jwk is a parsed key of the set
+type jwk struct {
+	key any
+	alg string
+}
+
+// NewJWKS returns a JWKS fetching the document from the URL when the first key is needed
+func NewJWKS(url string, options ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:                url,
+		client:             &http.Client{Timeout: DefaultJWKSTimeout},
+		refreshInterval:    time.Hour,
+		minRefreshInterval: time.Minute,
+	}
+	for _, option := range options {
+		option(j)
+	}
+	return j
+}
+
+// Key returns the key with the given ID, fetching the document if needed
+func (j *JWKS) Key(ctx context.Context, kid, alg string) (any, error) {
+	j.mu.Lock()
+	keys, expired := j.keys, j.keys == nil || now().Sub(j.fetchedAt) >= j.refreshInterval
+	j.mu.Unlock()
+	if !expired {
+		if key, found := lookup(keys, kid, alg); found {
+			return key, nil
+		}
+	}
+
+	// the cache expired or the issuer may have rotated its keys
+	if err := j.refresh(ctx); err != nil {
+		return nil, err
+	}
+	j.mu.Lock()
+	keys, err := j.keys, j.err
+	j.mu.Unlock()
+	if key, found := lookup(keys, kid, alg); found {
+		return key, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+}
+
+// refresh fetches the document unless it was attempted less than the minimum refresh interval ago,
+// or waits for the refresh in progress: it fails only if the context is done while waiting
+func (j *JWKS) refresh(ctx context.Context) error {
+	j.mu.Lock()
+	if refreshing := j.refreshing; refreshing != nil {
+		j.mu.Unlock()
+		select {
+		case <-refreshing:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ErrKeysUnavailable, ctx.Err())
+		}
+	}
+	if !j.attemptedAt.IsZero() && now().Sub(j.attemptedAt) < j.minRefreshInterval {
+		j.mu.Unlock()
+		return nil
+	}
+	j.attemptedAt = now()
+	done := make(chan struct{})
+	j.refreshing = done
+	j.mu.Unlock()
+
+	// the keys are shared by all the requests: the cancellation of the one triggering the fetch doesn't stop it
+	keys, err := j.fetch(context.WithoutCancel(ctx))
+	j.mu.Lock()
+	if err == nil {
+		j.keys, j.fetchedAt = keys, now()
+	}
+	j.err, j.refreshing = err, nil
+	j.mu.Unlock()
+	close(done)
+	return nil
+}
+
+func lookup(keys map[string]jwk, kid, alg string) (any, bool) {
+	if key, ok := keys[kid]; ok && (key.alg == "" || key.alg == alg) {
+		return key.key, true
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key.key, key.alg == "" || key.alg == alg
+		}
+	}
+	return nil, false
+}
+
+// fetch fetches and parses the document
+func (j *JWKS) fetch(ctx context.Context) (map[string]jwk, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+	response, err := j.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %s", ErrKeysUnavailable, j.url, response.Status)
+	}
+	keys, err := parseJWKS(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeysUnavailable, err)
+	}
+	return keys, nil
+}
+
+// rawJWK is a JSON Web Key as found in the documents
+type rawJWK struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid,omitempty"`
+	Use     string `json:"use,omitempty"`
+	Alg     string `json:"alg,omitempty"`
+	Curve   string `json:"crv,omitempty"`
+	N       string `json:"n,omitempty"`
+	E       string `json:"e,omitempty"`
+	X       string `json:"x,omitempty"`
+	Y       string `json:"y,omitempty"`
+}
+
+// parseJWKS decodes a JSON Web Key Set document into keys by ID: the keys not used for signatures
+// and the ones of unsupported types are skipped, including the symmetric keys which must never be published.
+// The documents with two keys with the same ID are rejected, since the key verifying a token would be ambiguous.
+func parseJWKS(r io.Reader) (map[string]jwk, error) {
+	var document struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("jwt: invalid JWKS document: %w", err)
+	}
+	keys := map[string]jwk{}
+	for _, raw := range document.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.parse()
+		if err != nil {
+			return nil, fmt.Errorf("jwt: invalid key %q: %w", raw.KeyID, err)
+		}
+		if key == nil {
+			continue
+		}
+		if _, ok := keys[raw.KeyID]; ok {
+			return nil, fmt.Errorf("jwt: invalid JWKS document: duplicate key ID %q", raw.KeyID)
+		}
+		keys[raw.KeyID] = jwk{key: key, alg: raw.Alg}
+	}
+	return keys, nil
+}
+
+// parse returns the key, nil if its type isn't supported
+func (raw rawJWK) parse() (any, error) {
+	switch {
+	case raw.KeyType == "RSA":
+		n, err := decodeSegment(raw.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(raw.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("%w: invalid RSA exponent", ErrMalformed)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case raw.KeyType == "EC" && raw.Curve == "P-256":
+		x, err := decodeSegment(raw.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(raw.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, fmt.Errorf("%w: invalid P-256 coordinates", ErrMalformed)
+		}
+		// crypto/ecdh checks that the point is on the curve
+		if _, err := ecdh.P256().NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case raw.KeyType == "OKP" && raw.Curve == "Ed25519":
+		x, err := decodeSegment(raw.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key", ErrMalformed)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,249 @@
+package jwt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer serves a JWKS document which the tests can change, counting the fetches
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	document map[string]any
+	status   int
+	fetches  int
+}
+
+func newJWKSServer(t *testing.T, keys ...map[string]any) *jwksServer {
+	s := &jwksServer{document: map[string]any{"keys": keys}, status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetches++
+		w.WriteHeader(s.status)
+		json.NewEncoder(w).Encode(s.document)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(status int, keys ...map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.document = status, map[string]any{"keys": keys}
+}
+
+func (s *jwksServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func b64(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func rsaJWK(kid string) map[string]any {
+	return map[string]any{"kty": "RSA", "kid": kid, "use": "sig", "alg": RS256, "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())}
+}
+
+func ecJWK(kid string) map[string]any {
+	x, y := make([]byte, 32), make([]byte, 32)
+	ecdsaKey.X.FillBytes(x)
+	ecdsaKey.Y.FillBytes(y)
+	return map[string]any{"kty": "EC", "kid": kid, "crv": "P-256", "x": b64(x), "y": b64(y)}
+}
+
+func okpJWK(kid string) map[string]any {
+	return map[string]any{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(ed25519Key[32:])}
+}
+
+func TestJWKS(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, at)
+	server := newJWKSServer(t, rsaJWK("rsa"), ecJWK("ec"), okpJWK("ed"),
+		map[string]any{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		map[string]any{"kty": "EC", "kid": "p384", "crv": "P-384", "x": "AA", "y": "AA"})
+	jwks := NewJWKS(server.URL, WithHTTPClient(server.Client()), WithRefreshInterval(time.Hour), WithMinRefreshInterval(time.Minute))
+	verifier := NewVerifier(jwks)
+
+	for _, test := range []struct {
+		kid string
+		alg string
+		key any
+	}{{"rsa", RS256, rsaKey}, {"ec", ES256, ecdsaKey}, {"ed", EdDSA, ed25519Key}} {
+		token := signToken(t, map[string]any{"alg": test.alg, "kid": test.kid}, map[string]any{"sub": "alice"}, test.key)
+		if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.kid, err)
+		}
+	}
+	if server.count() != 1 {
+		t.Errorf("%s failed: expected the document to be fetched once, got %d fetches", t.Name(), server.count())
+	}
+
+	// the key of another algorithm isn't used
+	token := signToken(t, map[string]any{"alg": ES256, "kid": "rsa"}, map[string]any{}, ecdsaKey)
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), ErrKeyNotFound, err)
+	}
+
+	// an unknown kid triggers a refresh, at most once per minute
+	server.set(http.StatusOK, rsaJWK("rotated"))
+	rotated := signToken(t, map[string]any{"alg": RS256, "kid": "rotated"}, map[string]any{}, rsaKey)
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, rotated); !errors.Is(err, ErrKeyNotFound) || server.count() != 1 {
+		t.Errorf("%s failed: expected no refresh within a minute, got %v after %d fetches", t.Name(), err, server.count())
+	}
+	fixClock(t, at.Add(time.Minute))
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, rotated); err != nil || server.count() != 2 {
+		t.Errorf("%s failed: expected the rotated key to be fetched, got %v after %d fetches", t.Name(), err, server.count())
+	}
+
+	// the cached keys are kept when the refresh fails
+	server.set(http.StatusInternalServerError)
+	fixClock(t, at.Add(2*time.Hour))
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, rotated); err != nil || server.count() != 3 {
+		t.Errorf("%s failed: expected the cached key after a failed refresh, got %v after %d fetches", t.Name(), err, server.count())
+	}
+}
+
+func TestJWKSSlowRefresh(t *testing.T) {
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first fetch is immediate, the following ones wait to be released
+		if fetches.Add(1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]any{rsaJWK("rsa")}})
+	}))
+	defer server.Close()
+	defer close(release)
+	verifier := NewVerifier(NewJWKS(server.URL, WithHTTPClient(server.Client()), WithMinRefreshInterval(0)))
+	cached := signToken(t, map[string]any{"alg": RS256, "kid": "rsa"}, map[string]any{}, rsaKey)
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, cached); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+
+	// an unknown kid triggers a refresh, which waits for the server
+	unknown := signToken(t, map[string]any{"alg": RS256, "kid": "unknown"}, map[string]any{}, rsaKey)
+	go Parse[RegisteredClaims](context.Background(), verifier, unknown)
+	for fetches.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := Parse[RegisteredClaims](context.Background(), verifier, cached)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("%s failed: unexpected error %v", t.Name(), err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("%s failed: the cached key waited for the refresh", t.Name())
+	}
+
+	// the requests waiting for the refresh give up when their context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := Parse[RegisteredClaims](ctx, verifier, unknown); !errors.Is(err, ErrKeysUnavailable) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), ErrKeysUnavailable, err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("%s failed: expected the refresh in progress to be shared, got %d fetches", t.Name(), fetches.Load())
+	}
+	if client := NewJWKS(server.URL).client; client.Timeout != DefaultJWKSTimeout {
+		t.Errorf("%s failed: expected the default client to time out, got %v", t.Name(), client.Timeout)
+	}
+}
+
+func TestJWKSUnavailable(t *testing.T) {
+	server := newJWKSServer(t)
+	server.set(http.StatusServiceUnavailable)
+	verifier := NewVerifier(NewJWKS(server.URL, WithHTTPClient(server.Client())))
+	token := signToken(t, map[string]any{"alg": RS256, "kid": "rsa"}, map[string]any{}, rsaKey)
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); !errors.Is(err, ErrKeysUnavailable) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), ErrKeysUnavailable, err)
+	}
+}
+
+func TestJWKSRejectedKeys(t *testing.T) {
+	secret := map[string]any{"kty": "oct", "kid": "hmac", "k": b64(hmacSecret)}
+	for _, test := range []struct {
+		description string
+		keys        []map[string]any
+		kid         string
+		alg         string
+		key         any
+		expectedErr error
+	}{
+		{"symmetric key", []map[string]any{rsaJWK("rsa"), secret}, "hmac", HS256, hmacSecret, ErrKeyNotFound},
+		{"symmetric key without kid", []map[string]any{{"kty": "oct", "k": b64(hmacSecret)}}, "", HS256, hmacSecret, ErrKeyNotFound},
+		{"duplicate kid", []map[string]any{rsaJWK("key"), ecJWK("key")}, "key", RS256, rsaKey, ErrKeysUnavailable},
+		{"duplicate empty kid", []map[string]any{rsaJWK(""), ecJWK("")}, "", RS256, rsaKey, ErrKeysUnavailable},
+	} {
+		server := newJWKSServer(t, test.keys...)
+		verifier := NewVerifier(NewJWKS(server.URL, WithHTTPClient(server.Client())))
+		token := signToken(t, map[string]any{"alg": test.alg, "kid": test.kid}, map[string]any{}, test.key)
+		if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), test.description, test.expectedErr, err)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	server := newJWKSServer(t, rsaJWK("rsa"))
+	verifier := NewVerifier(NewJWKS(server.URL, WithHTTPClient(server.Client())), WithAudience("api"))
+	var subject string
+	handler := Middleware[customClaims](http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext[customClaims](r.Context())
+		subject = claims.Subject
+	}), verifier)
+
+	tests := []struct {
+		testName  string
+		token     string
+		expected  int
+		challenge string
+	}{
+		{"valid token", signToken(t, map[string]any{"alg": RS256, "kid": "rsa"}, map[string]any{"sub": "alice", "aud": "api"}, rsaKey), http.StatusOK, ""},
+		{"missing token", "", http.StatusUnauthorized, "Bearer"},
+		{"wrong audience", signToken(t, map[string]any{"alg": RS256, "kid": "rsa"}, map[string]any{"sub": "alice", "aud": "web"}, rsaKey), http.StatusUnauthorized, `Bearer error="invalid_token"`},
+	}
+	for _, test := range tests {
+		subject = ""
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.token != "" {
+			request.Header.Set("Authorization", "Bearer "+test.token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != test.expected || recorder.Header().Get("WWW-Authenticate") != test.challenge {
+			t.Errorf("%s failed (%s): expected %d %q, got %d %q", t.Name(), test.testName, test.expected, test.challenge, recorder.Code, recorder.Header().Get("WWW-Authenticate"))
+		}
+		if test.expected == http.StatusOK && subject != "alice" {
+			t.Errorf("%s failed (%s): expected the claims in the context, got subject %q", t.Name(), test.testName, subject)
+		}
+	}
+
+	server.Close()
+	unavailable := Middleware[customClaims](http.NotFoundHandler(), NewVerifier(NewJWKS(server.URL)))
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+tests[0].token)
+	recorder := httptest.NewRecorder()
+	unavailable.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("%s failed: expected %d with the keys unavailable, got %d", t.Name(), http.StatusServiceUnavailable, recorder.Code)
+	}
+}
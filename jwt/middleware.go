@@ -0,0 +1,43 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// claimsKey is the context key of the claims of type T stored by the Middleware
+type claimsKey[T any] struct{}
+
+// ClaimsFromContext returns the claims stored by a Middleware of the same claims type
+func ClaimsFromContext[T any](ctx context.Context) (*T, bool) {
+	claims, ok := ctx.Value(claimsKey[T]{}).(*T)
+	return claims, ok
+}
+
+// Middleware verifies the bearer tokens of the requests, storing their claims of type T in the context.
+// The requests without a valid token are rejected with 401 Unauthorized and a WWW-Authenticate challenge (RFC 6750),
+// the ones whose token cannot be verified because the keys are unavailable with 503 Service Unavailable.
+func Middleware[T any](next http.Handler, v *Verifier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := utils.GetBearerToken(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		claims, err := Parse[T](r.Context(), v, token)
+		if errors.Is(err, ErrKeysUnavailable) {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey[T]{}, claims)))
+	})
+}
@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gyozatech/sushi/ids"
+)
+
+var (
+	// ErrInvalidRefreshToken is returned for the unknown and the revoked refresh tokens
+	ErrInvalidRefreshToken = errors.New("jwt: invalid refresh token")
+	// ErrRefreshTokenExpired is returned for the expired refresh tokens
+	ErrRefreshTokenExpired = errors.New("jwt: refresh token expired")
+	// ErrRefreshTokenReused is returned when a refresh token is used twice: the whole family of tokens is revoked,
+	// since either the legitimate client or an attacker holds a stolen token
+	ErrRefreshTokenReused = errors.New("jwt: refresh token reused")
+)
+
+// RefreshToken is the stored state of a refresh token. The tokens rotated from the same one belong to its family.
+type RefreshToken struct {
+	// ID is the SHA-256 of the token: the tokens themselves are never stored
+	ID        string
+	FamilyID  string
+	Subject   string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	// UsedAt is the time of the rotation of the token, zero if it hasn't been used yet
+	UsedAt  time.Time
+	Revoked bool
+}
+
+// RefreshStore stores the refresh tokens
+type RefreshStore interface {
+	// Create stores a new token
+	Create(ctx context.Context, token RefreshToken) error
+	// Get returns the token with the given ID, ErrInvalidRefreshToken if it doesn't exist
+	Get(ctx context.Context, id string) (RefreshToken, error)
+	// MarkUsed sets the use time of a token unless it's already set, returning false in that case:
+	// it must be atomic, so that a token cannot be rotated twice by concurrent requests
+	MarkUsed(ctx context.Context, id string, at time.Time) (bool, error)
+	// RevokeFamily revokes all the tokens of a family
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// RefreshTokens issues opaque refresh tokens and rotates them: every token can be exchanged only once for a new one,
+// and exchanging a token twice revokes all the tokens of its family (reuse detection)
+type RefreshTokens struct {
+	store RefreshStore
+	ttl   time.Duration
+}
+
+// NewRefreshTokens returns the manager of the refresh tokens of the store, which expire after the given time
+func NewRefreshTokens(store RefreshStore, ttl time.Duration) *RefreshTokens {
+	return &RefreshTokens{store: store, ttl: ttl}
+}
+
+// Issue returns a refresh token of a new family, e.g. after the user logs in
+func (r *RefreshTokens) Issue(ctx context.Context, subject string) (string, error) {
+	return r.create(ctx, ids.NewV4().String(), subject)
+}
+
+// Rotate exchanges a refresh token for a new one of the same family, returning the state of the new token.
+// It returns ErrRefreshTokenReused, revoking the family, if the token was already exchanged.
+// With a SQL store, it can be called within a sql.Transactor transaction: since the revocation of a reused token
+// is rolled back with the transaction, call Revoke with the token after the rollback.
+func (r *RefreshTokens) Rotate(ctx context.Context, token string) (string, RefreshToken, error) {
+	stored, err := r.store.Get(ctx, hashToken(token))
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+	if stored.Revoked {
+		return "", RefreshToken{}, ErrInvalidRefreshToken
+	}
+	current := now()
+	if !current.Before(stored.ExpiresAt) {
+		return "", RefreshToken{}, ErrRefreshTokenExpired
+	}
+	marked, err := r.store.MarkUsed(ctx, stored.ID, current)
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+	if !marked {
+		if err := r.store.RevokeFamily(ctx, stored.FamilyID); err != nil {
+			return "", RefreshToken{}, err
+		}
+		return "", RefreshToken{}, ErrRefreshTokenReused
+	}
+	rotated, err := r.create(ctx, stored.FamilyID, stored.Subject)
+	if err != nil {
+		return "", RefreshToken{}, err
+	}
+	state, err := r.store.Get(ctx, hashToken(rotated))
+	return rotated, state, err
+}
+
+// Revoke revokes the family of a refresh token, e.g. when the user logs out
+func (r *RefreshTokens) Revoke(ctx context.Context, token string) error {
+	stored, err := r.store.Get(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	return r.store.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func (r *RefreshTokens) create(ctx context.Context, familyID, subject string) (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("jwt: cannot generate the refresh token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+	current := now()
+	err := r.store.Create(ctx, RefreshToken{
+		ID:        hashToken(token),
+		FamilyID:  familyID,
+		Subject:   subject,
+		CreatedAt: current,
+		ExpiresAt: current.Add(r.ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryRefreshStore is a RefreshStore keeping the tokens in memory, for tests and single-instance services
+type MemoryRefreshStore struct {
+	mu     sync.Mutex
+	tokens map[string]RefreshToken
+}
+
+// NewMemoryRefreshStore returns an empty store
+func NewMemoryRefreshStore() *MemoryRefreshStore {
+	return &MemoryRefreshStore{tokens: map[string]RefreshToken{}}
+}
+
+// Create stores a new token, removing the expired ones
+func (m *MemoryRefreshStore) Create(ctx context.Context, token RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current := now()
+	for id, t := range m.tokens {
+		if !current.Before(t.ExpiresAt) {
+			delete(m.tokens, id)
+		}
+	}
+	m.tokens[token.ID] = token
+	return nil
+}
+
+// Get returns the token with the given ID
+func (m *MemoryRefreshStore) Get(ctx context.Context, id string) (RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[id]
+	if !ok {
+		return RefreshToken{}, ErrInvalidRefreshToken
+	}
+	return token, nil
+}
+
+// MarkUsed sets the use time of a token unless it's already set
+func (m *MemoryRefreshStore) MarkUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.tokens[id]
+	if !ok {
+		return false, ErrInvalidRefreshToken
+	}
+	if !token.UsedAt.IsZero() {
+		return false, nil
+	}
+	token.UsedAt = at
+	m.tokens[id] = token
+	return true, nil
+}
+
+// RevokeFamily revokes all the tokens of a family
+func (m *MemoryRefreshStore) RevokeFamily(ctx context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, token := range m.tokens {
+		if token.FamilyID == familyID {
+			token.Revoked = true
+			m.tokens[id] = token
+		}
+	}
+	return nil
+}
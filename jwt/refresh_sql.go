@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sqlutil "github.com/gyozatech/sushi/sql"
+)
+
+/*
+	SQLRefreshStore keeps the refresh tokens in a table like:
+
+		CREATE TABLE refresh_tokens (
+			id         VARCHAR(64) PRIMARY KEY,
+			family_id  VARCHAR(36) NOT NULL,
+			subject    VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			used_at    TIMESTAMP NULL,
+			revoked    BOOLEAN NOT NULL
+		);
+		CREATE INDEX refresh_tokens_family_id ON refresh_tokens (family_id);
+
+	The queries run within the transaction of the context, if any, so that the rotations can be part of a
+	sql.Transactor transaction. The revocation of a reused token is rolled back with the transaction:
+	revoke the token again with RefreshTokens.Revoke after the rollback.
+*/
+
+// SQLRefreshStore is a RefreshStore keeping the tokens in a SQL table
+type SQLRefreshStore struct {
+	db     *sql.DB
+	table  string
+	dollar bool
+}
+
+// SQLRefreshStoreOption configures a SQLRefreshStore
+type SQLRefreshStoreOption func(*SQLRefreshStore)
+
+// WithDollarPlaceholders uses the $1, $2... placeholders (e.g. PostgreSQL) instead of ?
+func WithDollarPlaceholders() SQLRefreshStoreOption {
+	return func(s *SQLRefreshStore) {
+		s.dollar = true
+	}
+}
+
+// NewSQLRefreshStore returns a store using the given table
+func NewSQLRefreshStore(db *sql.DB, table string, options ...SQLRefreshStoreOption) *SQLRefreshStore {
+	s := &SQLRefreshStore{db: db, table: table}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Create stores a new token
+func (s *SQLRefreshStore) Create(ctx context.Context, token RefreshToken) error {
+	query := s.query("INSERT INTO %s (id, family_id, subject, created_at, expires_at, used_at, revoked) VALUES (?, ?, ?, ?, ?, NULL, ?)")
+	_, err := sqlutil.Executor(ctx, s.db).ExecContext(ctx, query,
+		token.ID, token.FamilyID, token.Subject, token.CreatedAt, token.ExpiresAt, token.Revoked)
+	if err != nil {
+		return fmt.Errorf("jwt: cannot store the refresh token: %w", err)
+	}
+	return nil
+}
+
+// Get returns the token with the given ID
+func (s *SQLRefreshStore) Get(ctx context.Context, id string) (RefreshToken, error) {
+	query := s.query("SELECT id, family_id, subject, created_at, expires_at, used_at, revoked FROM %s WHERE id = ?")
+	var token RefreshToken
+	var usedAt sql.NullTime
+	err := sqlutil.Executor(ctx, s.db).QueryRowContext(ctx, query, id).
+		Scan(&token.ID, &token.FamilyID, &token.Subject, &token.CreatedAt, &token.ExpiresAt, &usedAt, &token.Revoked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RefreshToken{}, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return RefreshToken{}, fmt.Errorf("jwt: cannot read the refresh token: %w", err)
+	}
+	token.UsedAt = usedAt.Time
+	return token, nil
+}
+
+// MarkUsed sets the use time of a token unless it's already set, with a conditional update
+func (s *SQLRefreshStore) MarkUsed(ctx context.Context, id string, at time.Time) (bool, error) {
+	query := s.query("UPDATE %s SET used_at = ? WHERE id = ? AND used_at IS NULL")
+	result, err := sqlutil.Executor(ctx, s.db).ExecContext(ctx, query, at, id)
+	if err != nil {
+		return false, fmt.Errorf("jwt: cannot update the refresh token: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("jwt: cannot update the refresh token: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// RevokeFamily revokes all the tokens of a family
+func (s *SQLRefreshStore) RevokeFamily(ctx context.Context, familyID string) error {
+	query := s.query("UPDATE %s SET revoked = ? WHERE family_id = ?")
+	if _, err := sqlutil.Executor(ctx, s.db).ExecContext(ctx, query, true, familyID); err != nil {
+		return fmt.Errorf("jwt: cannot revoke the refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// query fills the table name and numbers the placeholders if needed
+func (s *SQLRefreshStore) query(format string) string {
+	query := fmt.Sprintf(format, s.table)
+	if !s.dollar {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, c := range query {
+		if c == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,315 @@
+package jwt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sqlutil "github.com/gyozatech/sushi/sql"
+)
+
+func TestRefreshTokens(t *testing.T) {
+	for name, store := range map[string]RefreshStore{"memory": NewMemoryRefreshStore(), "sql": newSQLRefreshStore(t)} {
+		at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		fixClock(t, at)
+		ctx := context.Background()
+		refresh := NewRefreshTokens(store, time.Hour)
+
+		first, err := refresh.Issue(ctx, "alice")
+		if err != nil {
+			t.Fatalf("%s failed (%s): unexpected error %v", t.Name(), name, err)
+		}
+		second, state, err := refresh.Rotate(ctx, first)
+		if err != nil || second == first || state.Subject != "alice" || !state.ExpiresAt.Equal(at.Add(time.Hour)) {
+			t.Errorf("%s failed (%s): unexpected rotation to %+v (%v)", t.Name(), name, state, err)
+		}
+		third, _, err := refresh.Rotate(ctx, second)
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), name, err)
+		}
+
+		// reusing a rotated token revokes the whole family
+		if _, _, err := refresh.Rotate(ctx, first); !errors.Is(err, ErrRefreshTokenReused) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), name, ErrRefreshTokenReused, err)
+		}
+		if _, _, err := refresh.Rotate(ctx, third); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("%s failed (%s): expected %v after the reuse, got %v", t.Name(), name, ErrInvalidRefreshToken, err)
+		}
+
+		// the other families are not affected
+		other, _ := refresh.Issue(ctx, "bob")
+		if _, _, err := refresh.Rotate(ctx, "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), name, ErrInvalidRefreshToken, err)
+		}
+		fixClock(t, at.Add(time.Hour))
+		if _, _, err := refresh.Rotate(ctx, other); !errors.Is(err, ErrRefreshTokenExpired) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), name, ErrRefreshTokenExpired, err)
+		}
+
+		// revoking a token revokes its family
+		revoked, _ := refresh.Issue(ctx, "carol")
+		if err := refresh.Revoke(ctx, revoked); err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), name, err)
+		}
+		if _, _, err := refresh.Rotate(ctx, revoked); !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("%s failed (%s): expected %v, got %v", t.Name(), name, ErrInvalidRefreshToken, err)
+		}
+	}
+}
+
+func TestRefreshTokensConcurrentRotation(t *testing.T) {
+	ctx := context.Background()
+	refresh := NewRefreshTokens(NewMemoryRefreshStore(), time.Hour)
+	token, _ := refresh.Issue(ctx, "alice")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := refresh.Rotate(ctx, token)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	rotated := 0
+	for err := range errs {
+		if err == nil {
+			rotated++
+		} else if !errors.Is(err, ErrRefreshTokenReused) && !errors.Is(err, ErrInvalidRefreshToken) {
+			t.Errorf("%s failed: unexpected error %v", t.Name(), err)
+		}
+	}
+	if rotated != 1 {
+		t.Errorf("%s failed: expected a single rotation, got %d", t.Name(), rotated)
+	}
+}
+
+func TestSQLRefreshStoreQueries(t *testing.T) {
+	db, table := newTestDB(t)
+	store := NewSQLRefreshStore(db, "refresh_tokens", WithDollarPlaceholders())
+	transactor := sqlutil.NewTransactor(db)
+	err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := store.MarkUsed(ctx, "id", time.Now())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	expected := "UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL"
+	if queries := table.queries; len(queries) != 1 || queries[0] != expected+" (tx)" {
+		t.Errorf("%s failed: expected %q within the transaction, got %q", t.Name(), expected, queries)
+	}
+}
+
+func TestRefreshTokensReuseInTransaction(t *testing.T) {
+	db, _ := newTestDB(t)
+	// the revocation must use the connection of the transaction
+	db.SetMaxOpenConns(1)
+	refresh := NewRefreshTokens(NewSQLRefreshStore(db, "refresh_tokens"), time.Hour)
+	transactor := sqlutil.NewTransactor(db)
+	rotate := func(token string) (string, error) {
+		var rotated string
+		err := transactor.WithinTransaction(context.Background(), func(ctx context.Context) (err error) {
+			rotated, _, err = refresh.Rotate(ctx, token)
+			return err
+		})
+		return rotated, err
+	}
+
+	first, _ := refresh.Issue(context.Background(), "alice")
+	second, err := rotate(first)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, _, err := refresh.Rotate(ctx, first)
+		return err
+	})
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("%s failed: expected %v, got %v", t.Name(), ErrRefreshTokenReused, err)
+	}
+	// the revocation was rolled back with the transaction: the caller revokes the token again
+	if err := refresh.Revoke(ctx, first); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if _, err := rotate(second); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("%s failed: expected the family revoked after the rollback, got %v", t.Name(), err)
+	}
+}
+
+func newSQLRefreshStore(t *testing.T) *SQLRefreshStore {
+	db, _ := newTestDB(t)
+	return NewSQLRefreshStore(db, "refresh_tokens")
+}
+
+// testStore is the table of a test database, which understands the queries of SQLRefreshStore only
+type testStore struct {
+	mu      sync.Mutex
+	rows    map[string][]driver.Value
+	queries []string
+}
+
+// testStores holds the tables of the test databases by name
+var testStores sync.Map
+
+// newTestDB opens a test database with an empty table
+func newTestDB(t *testing.T) (*sql.DB, *testStore) {
+	name := fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())
+	store := &testStore{rows: map[string][]driver.Value{}}
+	testStores.Store(name, store)
+	db, err := sql.Open("jwt-test", name)
+	if err != nil {
+		t.Fatalf("cannot open the test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, store
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(name string) (driver.Conn, error) {
+	store, _ := testStores.Load(name)
+	return &testConn{store: store.(*testStore)}, nil
+}
+
+// testConn keeps the previous rows changed within a transaction, to restore them on rollback
+type testConn struct {
+	store *testStore
+	tx    bool
+	undo  map[string][]driver.Value
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) { return &testStmt{c, query}, nil }
+func (c *testConn) Close() error                              { return nil }
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	c.tx, c.undo = true, map[string][]driver.Value{}
+	return c, nil
+}
+
+func (c *testConn) Commit() error {
+	c.tx, c.undo = false, nil
+	return nil
+}
+
+func (c *testConn) Rollback() error {
+	c.store.mu.Lock()
+	defer c.store.mu.Unlock()
+	for id, row := range c.undo {
+		if row == nil {
+			delete(c.store.rows, id)
+		} else {
+			c.store.rows[id] = row
+		}
+	}
+	c.tx, c.undo = false, nil
+	return nil
+}
+
+// change saves the row before it's changed within a transaction
+func (c *testConn) change(id string) {
+	if _, saved := c.undo[id]; c.tx && !saved {
+		if row, ok := c.store.rows[id]; ok {
+			c.undo[id] = append([]driver.Value{}, row...)
+		} else {
+			c.undo[id] = nil
+		}
+	}
+}
+
+type testStmt struct {
+	conn  *testConn
+	query string
+}
+
+func (s *testStmt) Close() error  { return nil }
+func (s *testStmt) NumInput() int { return -1 }
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	store := s.conn.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s.record()
+	var affected int64
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		s.conn.change(args[0].(string))
+		store.rows[args[0].(string)] = []driver.Value{args[0], args[1], args[2], args[3], args[4], nil, args[5]}
+		affected = 1
+	case strings.Contains(s.query, "SET used_at"):
+		if row, ok := store.rows[args[1].(string)]; ok && row[5] == nil {
+			s.conn.change(args[1].(string))
+			row[5] = args[0]
+			affected = 1
+		}
+	case strings.Contains(s.query, "SET revoked"):
+		for id, row := range store.rows {
+			if row[1] == args[1] {
+				s.conn.change(id)
+				row[6] = args[0]
+				affected++
+			}
+		}
+	default:
+		return nil, errors.New("unsupported query")
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	store := s.conn.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s.record()
+	if !strings.HasPrefix(s.query, "SELECT") {
+		return nil, errors.New("unsupported query")
+	}
+	rows := &testRows{}
+	if row, ok := store.rows[args[0].(string)]; ok {
+		rows.rows = append(rows.rows, append([]driver.Value{}, row...))
+	}
+	return rows, nil
+}
+
+func (s *testStmt) record() {
+	query := s.query
+	if s.conn.tx {
+		query += " (tx)"
+	}
+	s.conn.store.queries = append(s.conn.store.queries, query)
+}
+
+type testRows struct {
+	rows [][]driver.Value
+}
+
+func (r *testRows) Columns() []string {
+	return []string{"id", "family_id", "subject", "created_at", "expires_at", "used_at", "revoked"}
+}
+
+func (r *testRows) Close() error { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("jwt-test", testDriver{})
+}
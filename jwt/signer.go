@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// SigningKey is a private key identified by the kid header of the tokens it signs:
+// a []byte secret of at least 32 bytes for HS256, an *rsa.PrivateKey of at least 2048 bits for RS256,
+// a P-256 *ecdsa.PrivateKey for ES256 or an ed25519.PrivateKey for EdDSA
+type SigningKey struct {
+	ID        string
+	Algorithm string
+	Key       any
+}
+
+// Signer signs tokens with its current key. After a rotation the previous keys are still used to verify the tokens
+// they signed, until they are retired: the Signer is a KeySet, and it publishes its public keys as a JWKS document.
+// It's safe for concurrent use.
+type Signer struct {
+	mu      sync.RWMutex
+	current SigningKey
+	keys    map[string]SigningKey
+}
+
+// NewSigner returns a Signer using the given key
+func NewSigner(key SigningKey) (*Signer, error) {
+	s := &Signer{keys: map[string]SigningKey{}}
+	if err := s.Rotate(key); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Rotate makes the key the current one: its ID must differ from the ones of the keys not retired yet
+func (s *Signer) Rotate(key SigningKey) error {
+	if key.ID == "" {
+		return fmt.Errorf("jwt: the signing key has no ID")
+	}
+	if err := checkSigningKey(key.Algorithm, key.Key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.keys[key.ID]; ok {
+		return fmt.Errorf("jwt: duplicate key ID %q", key.ID)
+	}
+	s.current = key
+	s.keys[key.ID] = key
+	return nil
+}
+
+// Retire removes a previous key, so that the tokens it signed are no longer valid: the current key cannot be retired
+func (s *Signer) Retire(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if kid == s.current.ID {
+		return fmt.Errorf("jwt: cannot retire the current key %q", kid)
+	}
+	delete(s.keys, kid)
+	return nil
+}
+
+// Sign returns a token holding the claims, e.g. a RegisteredClaims or a Claims built with NewClaims,
+// signed with the current key
+func (s *Signer) Sign(claims any) (string, error) {
+	s.mu.RLock()
+	key := s.current
+	s.mu.RUnlock()
+
+	header, err := json.Marshal(Header{Algorithm: key.Algorithm, Type: "JWT", KeyID: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt: cannot encode the claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := sign(key.Algorithm, key.Key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Key returns the key verifying the tokens signed with the key of the given ID
+func (s *Signer) Key(ctx context.Context, kid, alg string) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[kid]
+	if !ok || key.Algorithm != alg {
+		return nil, fmt.Errorf("%w: kid %q", ErrKeyNotFound, kid)
+	}
+	return publicKey(key.Key), nil
+}
+
+// JWKS returns the JSON Web Key Set document of the public keys: the HMAC secrets are never published
+func (s *Signer) JWKS() ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := []rawJWK{}
+	for _, key := range s.keys {
+		jwk := rawJWK{KeyID: key.ID, Use: "sig", Alg: key.Algorithm}
+		switch public := publicKey(key.Key).(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case *ecdsa.PublicKey:
+			x, y := make([]byte, 32), make([]byte, 32)
+			public.X.FillBytes(x)
+			public.Y.FillBytes(y)
+			jwk.KeyType, jwk.Curve = "EC", "P-256"
+			jwk.X, jwk.Y = base64.RawURLEncoding.EncodeToString(x), base64.RawURLEncoding.EncodeToString(y)
+		case ed25519.PublicKey:
+			jwk.KeyType, jwk.Curve = "OKP", "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		keys = append(keys, jwk)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyID < keys[j].KeyID })
+	return json.Marshal(map[string][]rawJWK{"keys": keys})
+}
+
+// JWKSHandler serves the JWKS document, e.g. at /.well-known/jwks.json
+func (s *Signer) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document, err := s.JWKS()
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	})
+}
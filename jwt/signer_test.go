@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roles struct {
+	Roles []string `json:"roles"`
+}
+
+func TestSigner(t *testing.T) {
+	tests := []struct {
+		alg string
+		key any
+	}{{HS256, hmacSecret}, {RS256, rsaKey}, {ES256, ecdsaKey}, {EdDSA, ed25519Key}}
+	for _, test := range tests {
+		signer, err := NewSigner(SigningKey{ID: "key-1", Algorithm: test.alg, Key: test.key})
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.alg, err)
+			continue
+		}
+		token, err := signer.Sign(NewClaims(roles{[]string{"admin"}}).Subject("alice").ExpiresIn(time.Minute).Build())
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.alg, err)
+			continue
+		}
+		claims, err := Parse[Claims[roles]](context.Background(), NewVerifier(signer), token)
+		if err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), test.alg, err)
+			continue
+		}
+		if claims.Subject != "alice" || len(claims.Custom.Roles) != 1 || claims.Custom.Roles[0] != "admin" {
+			t.Errorf("%s failed (%s): unexpected claims %+v", t.Name(), test.alg, claims)
+		}
+	}
+}
+
+func TestSignerRotation(t *testing.T) {
+	signer, _ := NewSigner(SigningKey{ID: "old", Algorithm: RS256, Key: rsaKey})
+	verifier := NewVerifier(signer)
+	old, _ := signer.Sign(RegisteredClaims{Subject: "alice"})
+	if err := signer.Rotate(SigningKey{ID: "new", Algorithm: ES256, Key: ecdsaKey}); err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	rotated, _ := signer.Sign(RegisteredClaims{Subject: "alice"})
+	if header, _ := decodeSegment(strings.Split(rotated, ".")[0]); !strings.Contains(string(header), `"kid":"new"`) {
+		t.Errorf("%s failed: expected the new kid, got header %s", t.Name(), header)
+	}
+
+	for name, token := range map[string]string{"old": old, "new": rotated} {
+		if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); err != nil {
+			t.Errorf("%s failed (%s): unexpected error %v", t.Name(), name, err)
+		}
+	}
+	if err := signer.Rotate(SigningKey{ID: "old", Algorithm: EdDSA, Key: ed25519Key}); err == nil {
+		t.Errorf("%s failed: expected an error for a duplicate kid", t.Name())
+	}
+	if err := signer.Retire("new"); err == nil {
+		t.Errorf("%s failed: expected an error retiring the current key", t.Name())
+	}
+	if err := signer.Retire("old"); err != nil {
+		t.Errorf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, old); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("%s failed: expected %v for a retired key, got %v", t.Name(), ErrKeyNotFound, err)
+	}
+}
+
+func TestSigningKeys(t *testing.T) {
+	weakRSA, _ := rsa.GenerateKey(rand.Reader, 1024)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	tests := []struct {
+		testName string
+		key      SigningKey
+	}{
+		{"missing ID", SigningKey{Algorithm: HS256, Key: hmacSecret}},
+		{"short secret", SigningKey{ID: "k", Algorithm: HS256, Key: []byte("secret")}},
+		{"weak RSA key", SigningKey{ID: "k", Algorithm: RS256, Key: weakRSA}},
+		{"public RSA key", SigningKey{ID: "k", Algorithm: RS256, Key: &rsaKey.PublicKey}},
+		{"P-384 key", SigningKey{ID: "k", Algorithm: ES256, Key: p384}},
+		{"mismatched algorithm", SigningKey{ID: "k", Algorithm: EdDSA, Key: ecdsaKey}},
+		{"unsupported algorithm", SigningKey{ID: "k", Algorithm: "none", Key: nil}},
+	}
+	for _, test := range tests {
+		if _, err := NewSigner(test.key); err == nil {
+			t.Errorf("%s failed (%s): expected an error", t.Name(), test.testName)
+		}
+	}
+}
+
+func TestSignerJWKS(t *testing.T) {
+	signer, _ := NewSigner(SigningKey{ID: "hmac", Algorithm: HS256, Key: hmacSecret})
+	for _, key := range []SigningKey{{"rsa", RS256, rsaKey}, {"ec", ES256, ecdsaKey}, {"ed", EdDSA, ed25519Key}} {
+		signer.Rotate(key)
+	}
+	document, err := signer.JWKS()
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	if strings.Contains(string(document), `"hmac"`) || strings.Contains(string(document), `"d"`) {
+		t.Errorf("%s failed: expected the public keys only, got %s", t.Name(), document)
+	}
+
+	server := httptest.NewServer(signer.JWKSHandler())
+	defer server.Close()
+	verifier := NewVerifier(NewJWKS(server.URL, WithHTTPClient(server.Client())))
+	token, _ := signer.Sign(RegisteredClaims{Subject: "alice"})
+	if _, err := Parse[RegisteredClaims](context.Background(), verifier, token); err != nil {
+		t.Errorf("%s failed: unexpected error %v", t.Name(), err)
+	}
+}
+
+func TestClaims(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	fixClock(t, at)
+
+	claims := NewClaims(roles{[]string{"admin"}}).Issuer("auth").Subject("alice").Audience("api", "web").
+		ExpiresIn(time.Hour).NotBefore(at).ID("token-1").Build()
+	data, err := json.Marshal(claims)
+	expected := `{"aud":["api","web"],"exp":1714561200,"iat":1714557600,"iss":"auth","jti":"token-1","nbf":1714557600,"roles":["admin"],"sub":"alice"}`
+	if err != nil || string(data) != expected {
+		t.Errorf("%s failed: expected %s, got %s (%v)", t.Name(), expected, data, err)
+	}
+
+	var decoded Claims[roles]
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Subject != "alice" || decoded.Custom.Roles[0] != "admin" {
+		t.Errorf("%s failed: unexpected decoded claims %+v (%v)", t.Name(), decoded, err)
+	}
+
+	if random := NewClaims(roles{}).Build(); random.ID == "" || !random.IssuedAt.Equal(at) {
+		t.Errorf("%s failed: expected a random jti issued now, got %+v", t.Name(), random.RegisteredClaims)
+	}
+
+	clash := NewClaims(map[string]string{"sub": "mallory"}).Subject("alice").Build()
+	if _, err := json.Marshal(clash); err == nil {
+		t.Errorf("%s failed: expected an error for a clashing custom claim", t.Name())
+	}
+}
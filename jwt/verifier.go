@@ -0,0 +1,108 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// VerifierOption customizes a Verifier
+type VerifierOption func(*Verifier)
+
+// WithAlgorithms restricts the accepted algorithms (HS256, RS256, ES256 and EdDSA by default)
+func WithAlgorithms(algorithms ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.algorithms = map[string]bool{}
+		for _, alg := range algorithms {
+			v.algorithms[alg] = true
+		}
+	}
+}
+
+// WithIssuer requires the iss claim to be the given issuer
+func WithIssuer(issuer string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires the aud claim to contain the given audience
+func WithAudience(audience string) VerifierOption {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// WithClockSkew tolerates the given difference between the clocks of the issuer and of the verifier
+// when validating the exp, nbf and iat claims
+func WithClockSkew(skew time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.skew = skew
+	}
+}
+
+// Verifier verifies the signature and the registered claims of the tokens
+type Verifier struct {
+	keys       KeySet
+	algorithms map[string]bool
+	issuer     string
+	audience   string
+	skew       time.Duration
+}
+
+// NewVerifier returns a Verifier of the tokens signed with the keys of the key set
+func NewVerifier(keys KeySet, options ...VerifierOption) *Verifier {
+	v := &Verifier{keys: keys, algorithms: map[string]bool{HS256: true, RS256: true, ES256: true, EdDSA: true}}
+	for _, option := range options {
+		option(v)
+	}
+	return v
+}
+
+// verify checks the token and returns its payload
+func (v *Verifier) verify(ctx context.Context, token string) ([]byte, error) {
+	header, payload, signature, signingInput, err := split(token)
+	if err != nil {
+		return nil, err
+	}
+	if !v.algorithms[header.Algorithm] {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, header.Algorithm)
+	}
+	key, err := v.keys.Key(ctx, header.KeyID, header.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Algorithm, key, []byte(signingInput), signature); err != nil {
+		return nil, err
+	}
+
+	var claims RegisteredClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: invalid registered claims: %v", ErrMalformed, err)
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (v *Verifier) validate(claims RegisteredClaims) error {
+	current := now()
+	if claims.ExpiresAt != nil && !current.Before(claims.ExpiresAt.Add(v.skew)) {
+		return fmt.Errorf("%w at %s", ErrExpired, claims.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if claims.NotBefore != nil && current.Add(v.skew).Before(claims.NotBefore.Time) {
+		return fmt.Errorf("%w: not before %s", ErrNotYetValid, claims.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if claims.IssuedAt != nil && current.Add(v.skew).Before(claims.IssuedAt.Time) {
+		return fmt.Errorf("%w: issued at %s", ErrNotYetValid, claims.IssuedAt.UTC().Format(time.RFC3339))
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return fmt.Errorf("%w %q", ErrInvalidIssuer, claims.Issuer)
+	}
+	if v.audience != "" && !claims.Audience.Contains(v.audience) {
+		return fmt.Errorf("%w %v", ErrInvalidAudience, []string(claims.Audience))
+	}
+	return nil
+}
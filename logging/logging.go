@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gyozatech/sushi/requestid"
+	sqlutil "github.com/gyozatech/sushi/sql"
+	"github.com/gyozatech/sushi/utils"
+)
+
+// TxKey is the name of the attribute identifying the SQL transaction of the context
+const TxKey = "tx_id"
+
+// Redacted replaces the values of the sensitive attributes
+const Redacted = "******"
+
+// Format is the output format of the loggers created by New
+type Format int
+
+const (
+	// Logfmt writes key=value pairs
+	Logfmt Format = iota
+	// JSON writes a JSON object per line
+	JSON
+)
+
+// Option customizes the Handler
+type Option func(*options)
+
+type options struct {
+	format      Format
+	level       slog.Leveler
+	contextKeys []string
+	redacted    map[string]bool
+	sampler     *sampler
+}
+
+// WithFormat sets the output format of the loggers created by New (Logfmt by default)
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithLevel sets the minimum level of the loggers created by New (slog.LevelInfo by default)
+func WithLevel(level slog.Leveler) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithContextKeys sets the "context-values" values added to the records: none by default, since they may hold
+// personal data (e.g. the JWT claims or the authenticated user)
+func WithContextKeys(keys ...string) Option {
+	return func(o *options) {
+		o.contextKeys = keys
+	}
+}
+
+// WithRedactedKeys replaces with ****** the values of the attributes (also in groups and in the context values)
+// whose keys match the given ones case-insensitively, e.g. "password" or "authorization"
+func WithRedactedKeys(keys ...string) Option {
+	return func(o *options) {
+		for _, key := range keys {
+			o.redacted[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// WithSampling limits the records of the same level and message logged in each period: the first ones are logged,
+// then one every thereafter (none if thereafter is 0). The errors are never sampled.
+func WithSampling(period time.Duration, first, thereafter int) Option {
+	return func(o *options) {
+		o.sampler = &sampler{period: period, first: first, thereafter: thereafter}
+	}
+}
+
+// New returns a logger writing to w in logfmt or JSON through a Handler
+func New(w io.Writer, opts ...Option) *slog.Logger {
+	o := newOptions(opts)
+	handlerOptions := &slog.HandlerOptions{Level: o.level}
+	var next slog.Handler = slog.NewTextHandler(w, handlerOptions)
+	if o.format == JSON {
+		next = slog.NewJSONHandler(w, handlerOptions)
+	}
+	return slog.New(&Handler{next: next, options: o})
+}
+
+// Handler is a slog.Handler enriching the records logged with a context (e.g. with logger.InfoContext(ctx, ...))
+// with the request ID, the SQL transaction and the selected "context-values" values the context holds.
+// These attributes are added at the top level even when the logger has groups, like requestid.NewHandler does.
+type Handler struct {
+	next    slog.Handler
+	options *options
+	// groups are the groups opened after the attributes passed to next, nested in the records by Handle
+	groups []group
+}
+
+type group struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// NewHandler wraps a slog.Handler
+func NewHandler(next slog.Handler, opts ...Option) *Handler {
+	return &Handler{next: next, options: newOptions(opts)}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{level: slog.LevelInfo, redacted: map[string]bool{}}
+	for _, option := range opts {
+		option(o)
+	}
+	return o
+}
+
+// Enabled reports whether the wrapped handler handles records of the given level
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle samples, enriches and redacts the record before passing it to the wrapped handler
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if h.options.sampler != nil && record.Level < slog.LevelError && !h.options.sampler.allow(record.Level, record.Message) {
+		return nil
+	}
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.redact(a))
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		values := append(append([]slog.Attr{}, h.groups[i].attrs...), attrs...)
+		attrs = []slog.Attr{{Key: h.groups[i].name, Value: slog.GroupValue(values...)}}
+	}
+	enriched := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	enriched.AddAttrs(attrs...)
+	enriched.AddAttrs(h.contextAttrs(ctx)...)
+	return h.next.Handle(ctx, enriched)
+}
+
+// WithAttrs returns a Handler with the given attributes, redacted
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redact(a)
+	}
+	if len(h.groups) == 0 {
+		return &Handler{next: h.next.WithAttrs(redacted), options: h.options}
+	}
+	groups := append([]group{}, h.groups...)
+	last := &groups[len(groups)-1]
+	last.attrs = append(append([]slog.Attr{}, last.attrs...), redacted...)
+	return &Handler{next: h.next, options: h.options, groups: groups}
+}
+
+// WithGroup returns a Handler with the given group, which doesn't contain the context attributes
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]group{}, h.groups...), group{name: name})
+	return &Handler{next: h.next, options: h.options, groups: groups}
+}
+
+func (h *Handler) contextAttrs(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs := []slog.Attr{}
+	if id, ok := requestid.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String(requestid.LogKey, id))
+	}
+	if tx, err := sqlutil.TxFromContext(ctx); err == nil {
+		// a transaction has no identifier: its address is unique among the running ones
+		attrs = append(attrs, slog.String(TxKey, fmt.Sprintf("%p", tx)))
+	}
+
+	if len(h.options.contextKeys) == 0 {
+		return attrs
+	}
+	values := utils.FetchContextValues(ctx)
+	for _, key := range h.options.contextKeys {
+		if value, ok := values[key]; ok {
+			attrs = append(attrs, h.redact(slog.Any(key, value)))
+		}
+	}
+	return attrs
+}
+
+// redact replaces the values of the sensitive attributes, looking into the groups
+func (h *Handler) redact(a slog.Attr) slog.Attr {
+	if len(h.options.redacted) == 0 {
+		return a
+	}
+	if h.options.redacted[strings.ToLower(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = h.redact(member)
+		}
+		return slog.Group(a.Key, redacted...)
+	}
+	return a
+}
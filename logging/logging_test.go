@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gyozatech/sushi/requestid"
+	sqlutil "github.com/gyozatech/sushi/sql"
+	"github.com/gyozatech/sushi/utils"
+)
+
+// newLogger returns a logger writing to a buffer without the time
+func newLogger(opts ...Option) (*slog.Logger, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	next := slog.NewTextHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey && len(groups) == 0 {
+			return slog.Attr{}
+		}
+		return a
+	}})
+	return slog.New(NewHandler(next, opts...)), &buffer
+}
+
+func TestContextAttributes(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), "abc-123")
+	ctx = utils.EnrichContextWithValue(ctx, "user", "alice")
+	ctx = utils.EnrichContextWithValue(ctx, "attempt", 2)
+
+	tests := []struct {
+		testName string
+		opts     []Option
+		ctx      context.Context
+		expected string
+	}{
+		{"no context values by default", nil, ctx, `level=INFO msg=hello order=42 request_id=abc-123`},
+		{"no context keys", []Option{WithContextKeys()}, ctx, `level=INFO msg=hello order=42 request_id=abc-123`},
+		{"selected context values", []Option{WithContextKeys("user", "missing")}, ctx, `level=INFO msg=hello order=42 request_id=abc-123 user=alice`},
+		{"empty context", []Option{WithContextKeys("user")}, context.Background(), `level=INFO msg=hello order=42`},
+	}
+	for _, test := range tests {
+		logger, buffer := newLogger(test.opts...)
+		logger.InfoContext(test.ctx, "hello", "order", 42)
+		if actual := strings.TrimSpace(buffer.String()); actual != test.expected {
+			t.Errorf("%s failed (%s): expected %s, got %s", t.Name(), test.testName, test.expected, actual)
+		}
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	logger, buffer := newLogger(WithRedactedKeys("password", "Authorization"), WithContextKeys("password"))
+	ctx := utils.EnrichContextWithValue(context.Background(), "password", "context-secret")
+
+	logger.With("authorization", "Bearer token").WithGroup("request").InfoContext(ctx, "login",
+		"user", "alice", "PASSWORD", "secret", slog.Group("headers", "authorization", "Basic abc", "accept", "*/*"))
+
+	expected := `level=INFO msg=login authorization=****** request.user=alice request.PASSWORD=****** request.headers.authorization=****** request.headers.accept=*/* password=******`
+	if actual := strings.TrimSpace(buffer.String()); actual != expected {
+		t.Errorf("%s failed: expected %s, got %s", t.Name(), expected, actual)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	logger, buffer := newLogger(WithContextKeys("user"))
+	ctx := requestid.NewContext(utils.EnrichContextWithValue(context.Background(), "user", "alice"), "abc-123")
+
+	logger.With("service", "orders").WithGroup("request").With("method", "GET").WithGroup("body").InfoContext(ctx, "received", "size", 10)
+	logger.WithGroup("empty").InfoContext(ctx, "no attributes")
+
+	expected := `level=INFO msg=received service=orders request.method=GET request.body.size=10 request_id=abc-123 user=alice
+level=INFO msg="no attributes" request_id=abc-123 user=alice`
+	if actual := strings.TrimSpace(buffer.String()); actual != expected {
+		t.Errorf("%s failed: expected\n%s\ngot\n%s", t.Name(), expected, actual)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = time.Now })
+
+	logger, buffer := newLogger(WithSampling(time.Second, 2, 3))
+	for i := 1; i <= 10; i++ {
+		logger.Info("tick", "i", i)
+		logger.Error("failure", "i", i)
+	}
+	at = at.Add(time.Second)
+	logger.Info("tick", "i", 11)
+
+	ticks, failures := []string{}, 0
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if strings.Contains(line, "msg=tick") {
+			ticks = append(ticks, line[strings.Index(line, "i="):])
+		} else {
+			failures++
+		}
+	}
+	if expected := []string{"i=1", "i=2", "i=5", "i=8", "i=11"}; fmt.Sprint(ticks) != fmt.Sprint(expected) {
+		t.Errorf("%s failed: expected %v, got %v", t.Name(), expected, ticks)
+	}
+	if failures != 10 {
+		t.Errorf("%s failed: expected all the 10 errors, got %d", t.Name(), failures)
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := New(&buffer, WithFormat(JSON), WithLevel(slog.LevelWarn), WithRedactedKeys("token"))
+	ctx := requestid.NewContext(context.Background(), "abc-123")
+	logger.InfoContext(ctx, "ignored")
+	logger.WarnContext(ctx, "slow", "token", "secret", "ms", 1500)
+
+	var record map[string]any
+	if err := json.Unmarshal(buffer.Bytes(), &record); err != nil {
+		t.Fatalf("%s failed: invalid JSON %s: %v", t.Name(), buffer.String(), err)
+	}
+	if record["msg"] != "slow" || record["level"] != "WARN" || record["token"] != Redacted || record["ms"] != 1500.0 || record["request_id"] != "abc-123" {
+		t.Errorf("%s failed: unexpected record %v", t.Name(), record)
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	db, err := sql.Open("logging-test", "")
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	defer tx.Rollback()
+
+	logger, buffer := newLogger()
+	logger.InfoContext(sqlutil.ContextWithTx(context.Background(), tx), "query")
+	if expected := fmt.Sprintf("level=INFO msg=query tx_id=%p", tx); strings.TrimSpace(buffer.String()) != expected {
+		t.Errorf("%s failed: expected %s, got %s", t.Name(), expected, buffer.String())
+	}
+}
+
+// testDriver is a database driver whose connections can only begin transactions
+type testDriver struct{}
+
+func (testDriver) Open(name string) (driver.Conn, error) { return testConn{}, nil }
+
+type testConn struct{}
+
+func (testConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (testConn) Close() error                              { return nil }
+func (testConn) Begin() (driver.Tx, error)                 { return testConn{}, nil }
+func (testConn) Commit() error                             { return nil }
+func (testConn) Rollback() error                           { return nil }
+
+func init() {
+	sql.Register("logging-test", testDriver{})
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// now is the clock of the sampler, replaced by the tests
+var now = time.Now
+
+// sampler counts the records of each level and message logged in the current period
+type sampler struct {
+	period     time.Duration
+	first      int
+	thereafter int
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[sampleKey]int
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+// allow tells whether a record should be logged: the counts are reset at every period,
+// so the memory they take is bounded by the distinct messages of a period
+func (s *sampler) allow(level slog.Level, message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := now(); s.counts == nil || current.Sub(s.start) >= s.period {
+		s.start = current
+		s.counts = map[sampleKey]int{}
+	}
+	key := sampleKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
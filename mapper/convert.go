@@ -0,0 +1,442 @@
+package mapper
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	anyType             = reflect.TypeOf((*any)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	errOverflow         = errors.New("value out of range")
+)
+
+// options are the per-field settings read from the `map` tag
+type options struct {
+	layout string
+}
+
+func (m *Mapper) convert(path string, src reflect.Value, dstType reflect.Type, opts options) (reflect.Value, error) {
+	for src.IsValid() && src.Kind() == reflect.Interface {
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		return reflect.Zero(dstType), nil
+	}
+	if converter, ok := m.converters[[2]reflect.Type{src.Type(), dstType}]; ok {
+		results := converter.Call([]reflect.Value{src})
+		if err, _ := results[1].Interface().(error); err != nil {
+			return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: err}
+		}
+		return results[0], nil
+	}
+
+	switch {
+	case dstType.Kind() == reflect.Pointer:
+		if src.Kind() == reflect.Pointer && src.IsNil() {
+			return reflect.Zero(dstType), nil
+		}
+		elem, err := m.convert(path, src, dstType.Elem(), opts)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(dstType.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case src.Kind() == reflect.Pointer:
+		if src.IsNil() {
+			return reflect.Zero(dstType), nil
+		}
+		return m.convert(path, src.Elem(), dstType, opts)
+	case dstType == anyType:
+		return m.toAny(path, src, opts)
+	case dstType.Kind() == reflect.Interface:
+		if !src.Type().Implements(dstType) {
+			return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType}
+		}
+		result := reflect.New(dstType).Elem()
+		result.Set(src)
+		return result, nil
+	case src.Type() == timeType || dstType == timeType:
+		return m.convertTime(path, src, dstType, opts)
+	}
+
+	switch dstType.Kind() {
+	case reflect.Struct:
+		switch src.Kind() {
+		case reflect.Struct:
+			return m.structToStruct(path, src, dstType)
+		case reflect.Map:
+			return m.mapToStruct(path, src, dstType)
+		}
+	case reflect.Map:
+		switch src.Kind() {
+		case reflect.Struct:
+			return m.structToMap(path, src, dstType)
+		case reflect.Map:
+			return m.mapToMap(path, src, dstType)
+		}
+	case reflect.Slice, reflect.Array:
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			if src.Type().ConvertibleTo(dstType) && src.Type().Elem() == dstType.Elem() && dstType.Kind() == reflect.Slice {
+				// same element type: a plain copy is enough
+				result := reflect.MakeSlice(dstType, src.Len(), src.Len())
+				reflect.Copy(result, src)
+				return result, nil
+			}
+			return m.sliceToSlice(path, src, dstType)
+		}
+	}
+	return m.convertScalar(path, src, dstType, opts)
+}
+
+// toAny converts a value for an interface destination: structs become maps and slices become []any
+func (m *Mapper) toAny(path string, src reflect.Value, opts options) (reflect.Value, error) {
+	var target reflect.Type
+	switch {
+	case src.Type() == timeType:
+		if opts.layout != "" {
+			target = reflect.TypeOf("")
+		}
+	case src.Type().Implements(textMarshalerType):
+	case src.Kind() == reflect.Struct:
+		target = reflect.TypeOf(map[string]any{})
+	case src.Kind() == reflect.Map && src.Type().Key().Kind() == reflect.String:
+		target = reflect.TypeOf(map[string]any{})
+	case src.Kind() == reflect.Slice && src.Type().Elem().Kind() != reflect.Uint8, src.Kind() == reflect.Array:
+		target = reflect.TypeOf([]any{})
+	}
+	result := reflect.New(anyType).Elem()
+	if target == nil {
+		result.Set(src)
+		return result, nil
+	}
+	converted, err := m.convert(path, src, target, opts)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	result.Set(converted)
+	return result, nil
+}
+
+func (m *Mapper) structToStruct(path string, src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	p := m.plan(src.Type(), dstType)
+	dst := reflect.New(dstType).Elem()
+	for _, field := range p.fields {
+		value, ok := fieldByIndex(src, field.src)
+		if !ok {
+			continue
+		}
+		converted, err := m.convert(join(path, field.name), value, field.dstType, field.options)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.FieldByIndex(field.dst).Set(converted)
+	}
+	return dst, nil
+}
+
+func (m *Mapper) mapToStruct(path string, src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	if src.Type().Key().Kind() != reflect.String {
+		return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: errors.New("map keys must be strings")}
+	}
+	info := m.structInfo(dstType)
+	dst := reflect.New(dstType).Elem()
+	iter := src.MapRange()
+	for iter.Next() {
+		field, ok := info.lookup(iter.Key().String())
+		if !ok {
+			continue
+		}
+		converted, err := m.convert(join(path, field.name), iter.Value(), field.typ, field.options)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.FieldByIndex(field.index).Set(converted)
+	}
+	return dst, nil
+}
+
+func (m *Mapper) structToMap(path string, src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	if dstType.Key().Kind() != reflect.String {
+		return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: errors.New("map keys must be strings")}
+	}
+	info := m.structInfo(src.Type())
+	dst := reflect.MakeMapWithSize(dstType, len(info.fields))
+	for _, field := range info.fields {
+		value, ok := fieldByIndex(src, field.index)
+		if !ok || (field.omitEmpty && value.IsZero()) {
+			continue
+		}
+		converted, err := m.convert(join(path, field.name), value, dstType.Elem(), field.options)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.SetMapIndex(reflect.ValueOf(field.name).Convert(dstType.Key()), converted)
+	}
+	return dst, nil
+}
+
+func (m *Mapper) mapToMap(path string, src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	if src.IsNil() {
+		return reflect.Zero(dstType), nil
+	}
+	dst := reflect.MakeMapWithSize(dstType, src.Len())
+	iter := src.MapRange()
+	for iter.Next() {
+		key, err := m.convert(path, iter.Key(), dstType.Key(), options{})
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		value, err := m.convert(join(path, fmt.Sprint(iter.Key())), iter.Value(), dstType.Elem(), options{})
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.SetMapIndex(key, value)
+	}
+	return dst, nil
+}
+
+func (m *Mapper) sliceToSlice(path string, src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	var dst reflect.Value
+	if dstType.Kind() == reflect.Array {
+		if src.Len() > dstType.Len() {
+			return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: errOverflow}
+		}
+		dst = reflect.New(dstType).Elem()
+	} else {
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			return reflect.Zero(dstType), nil
+		}
+		dst = reflect.MakeSlice(dstType, src.Len(), src.Len())
+	}
+	for i := 0; i < src.Len(); i++ {
+		converted, err := m.convert(join(path, strconv.Itoa(i)), src.Index(i), dstType.Elem(), options{})
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		dst.Index(i).Set(converted)
+	}
+	return dst, nil
+}
+
+func (m *Mapper) convertTime(path string, src reflect.Value, dstType reflect.Type, opts options) (reflect.Value, error) {
+	layout := opts.layout
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	fail := func(err error) (reflect.Value, error) {
+		return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: err}
+	}
+
+	if src.Type() == timeType {
+		t := src.Interface().(time.Time)
+		switch {
+		case dstType == timeType:
+			return src, nil
+		case dstType.Kind() == reflect.String:
+			return reflect.ValueOf(t.Format(layout)).Convert(dstType), nil
+		case isInteger(dstType.Kind()):
+			return m.convertScalar(path, reflect.ValueOf(t.Unix()), dstType, opts)
+		}
+		return fail(nil)
+	}
+
+	switch {
+	case src.Kind() == reflect.String && strings.TrimSpace(src.String()) == "":
+		return reflect.Zero(dstType), nil
+	case src.Kind() == reflect.String:
+		t, err := time.Parse(layout, src.String())
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(t), nil
+	case isInteger(src.Kind()):
+		return reflect.ValueOf(time.Unix(src.Convert(reflect.TypeOf(int64(0))).Int(), 0).UTC()), nil
+	case isFloat(src.Kind()):
+		// decoded JSON numbers are float64
+		seconds, fraction := math.Modf(src.Float())
+		return reflect.ValueOf(time.Unix(int64(seconds), int64(fraction*1e9)).UTC()), nil
+	}
+	return fail(nil)
+}
+
+func (m *Mapper) convertScalar(path string, src reflect.Value, dstType reflect.Type, opts options) (reflect.Value, error) {
+	fail := func(err error) (reflect.Value, error) {
+		return reflect.Value{}, &ConversionError{Path: path, From: src.Type(), To: dstType, Err: err}
+	}
+	dstKind := dstType.Kind()
+
+	// text (un)marshalers take precedence over the string conversions
+	if src.Kind() == reflect.String && reflect.PointerTo(dstType).Implements(textUnmarshalerType) {
+		dst := reflect.New(dstType)
+		if err := dst.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(src.String())); err != nil {
+			return fail(err)
+		}
+		return dst.Elem(), nil
+	}
+	if dstKind == reflect.String && src.Type().Implements(textMarshalerType) {
+		text, err := src.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(string(text)).Convert(dstType), nil
+	}
+
+	switch {
+	case src.Kind() == reflect.String && dstKind == reflect.String:
+		return src.Convert(dstType), nil
+	case src.Kind() == reflect.String && strings.TrimSpace(src.String()) == "":
+		// an empty string means the value is not set
+		return reflect.Zero(dstType), nil
+	case src.Kind() == reflect.String:
+		return parseString(src.String(), dstType, fail)
+	case dstKind == reflect.String:
+		switch {
+		case src.Type() == durationType:
+			return reflect.ValueOf(src.Interface().(time.Duration).String()).Convert(dstType), nil
+		case isInteger(src.Kind()) || isUnsigned(src.Kind()) || isFloat(src.Kind()) || src.Kind() == reflect.Bool:
+			return reflect.ValueOf(fmt.Sprint(src.Interface())).Convert(dstType), nil
+		}
+	case dstKind == reflect.Bool && src.Kind() == reflect.Bool:
+		return src.Convert(dstType), nil
+	case isNumber(dstKind) && isNumber(src.Kind()):
+		return convertNumber(src, dstType, fail)
+	case src.Type().ConvertibleTo(dstType) && src.Kind() == dstKind:
+		return src.Convert(dstType), nil
+	}
+	return fail(nil)
+}
+
+func parseString(s string, dstType reflect.Type, fail func(error) (reflect.Value, error)) (reflect.Value, error) {
+	s = strings.TrimSpace(s)
+	switch kind := dstType.Kind(); {
+	case dstType == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(d), nil
+	case kind == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(b).Convert(dstType), nil
+	case isInteger(kind):
+		i, err := strconv.ParseInt(s, 10, dstType.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(i).Convert(dstType), nil
+	case isUnsigned(kind):
+		u, err := strconv.ParseUint(s, 10, dstType.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(u).Convert(dstType), nil
+	case isFloat(kind):
+		f, err := strconv.ParseFloat(s, dstType.Bits())
+		if err != nil {
+			return fail(err)
+		}
+		return reflect.ValueOf(f).Convert(dstType), nil
+	}
+	return fail(nil)
+}
+
+// convertNumber converts between numeric types failing on overflows and on lossy float to integer conversions
+func convertNumber(src reflect.Value, dstType reflect.Type, fail func(error) (reflect.Value, error)) (reflect.Value, error) {
+	dst := reflect.New(dstType).Elem()
+	switch kind := dstType.Kind(); {
+	case isInteger(kind):
+		var i int64
+		switch {
+		case isInteger(src.Kind()):
+			i = src.Int()
+		case isUnsigned(src.Kind()):
+			if src.Uint() > math.MaxInt64 {
+				return fail(errOverflow)
+			}
+			i = int64(src.Uint())
+		default:
+			f := src.Float()
+			if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+				return fail(errOverflow)
+			}
+			i = int64(f)
+		}
+		if dst.OverflowInt(i) {
+			return fail(errOverflow)
+		}
+		dst.SetInt(i)
+	case isUnsigned(kind):
+		var u uint64
+		switch {
+		case isInteger(src.Kind()):
+			if src.Int() < 0 {
+				return fail(errOverflow)
+			}
+			u = uint64(src.Int())
+		case isUnsigned(src.Kind()):
+			u = src.Uint()
+		default:
+			f := src.Float()
+			if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+				return fail(errOverflow)
+			}
+			u = uint64(f)
+		}
+		if dst.OverflowUint(u) {
+			return fail(errOverflow)
+		}
+		dst.SetUint(u)
+	default:
+		var f float64
+		switch {
+		case isInteger(src.Kind()):
+			f = float64(src.Int())
+		case isUnsigned(src.Kind()):
+			f = float64(src.Uint())
+		default:
+			f = src.Float()
+		}
+		if dst.OverflowFloat(f) {
+			return fail(errOverflow)
+		}
+		dst.SetFloat(f)
+	}
+	return dst, nil
+}
+
+func isInteger(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return isInteger(kind) || isUnsigned(kind) || isFloat(kind)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,115 @@
+package mapper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ConversionError is returned when a value cannot be converted into the destination type
+type ConversionError struct {
+	// Path is the dotted path of the field which couldn't be converted, empty for the root value
+	Path string
+	From reflect.Type
+	To   reflect.Type
+	Err  error
+}
+
+func (e *ConversionError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "value"
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("mapper: cannot convert %s from %v to %v: %s", path, e.From, e.To, e.Err)
+	}
+	return fmt.Sprintf("mapper: cannot convert %s from %v to %v", path, e.From, e.To)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// Mapper converts values between structs, maps and scalar types, matching the struct fields by name or `map:"..."` tag.
+// The field plans of every pair of types are computed once and cached, so a Mapper should be reused.
+// A Mapper is safe for concurrent use, but the converters should be registered before using it.
+type Mapper struct {
+	converters map[[2]reflect.Type]reflect.Value
+	plans      sync.Map // map[[2]reflect.Type]*plan
+	structs    sync.Map // map[reflect.Type]*structInfo
+}
+
+// New creates a new Mapper
+func New() *Mapper {
+	return &Mapper{
+		converters: map[[2]reflect.Type]reflect.Value{},
+	}
+}
+
+var defaultMapper = New()
+
+// Default returns the Mapper used by the package-level functions, to register custom converters on it
+func Default() *Mapper {
+	return defaultMapper
+}
+
+// RegisterConverter registers a custom conversion from S to D, which takes precedence over the built-in ones
+func RegisterConverter[S any, D any](m *Mapper, fn func(S) (D, error)) {
+	key := [2]reflect.Type{reflect.TypeOf((*S)(nil)).Elem(), reflect.TypeOf((*D)(nil)).Elem()}
+	m.converters[key] = reflect.ValueOf(fn)
+	// the plans may have captured the previous behavior
+	m.plans = sync.Map{}
+}
+
+// Into converts src into the value pointed by dst
+func (m *Mapper) Into(src any, dst any) error {
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("mapper: destination must be a non-nil pointer, got %T", dst)
+	}
+	converted, err := m.convert("", reflect.ValueOf(src), target.Elem().Type(), options{})
+	if err != nil {
+		return err
+	}
+	target.Elem().Set(converted)
+	return nil
+}
+
+// ToMap converts a struct (or a map) into a map[string]any: the nested structs are converted into maps too
+func (m *Mapper) ToMap(src any) (map[string]any, error) {
+	result := map[string]any{}
+	if err := m.Into(src, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Convert converts src into a new value of type D using the default Mapper.
+// Its signature makes it usable as a functional.Function[S, D].
+func Convert[S any, D any](src S) (*D, error) {
+	return ConvertWith[S, D](defaultMapper, src)
+}
+
+// ConvertWith converts src into a new value of type D using the given Mapper
+func ConvertWith[S any, D any](m *Mapper, src S) (*D, error) {
+	var dst D
+	if err := m.Into(src, &dst); err != nil {
+		return nil, err
+	}
+	return &dst, nil
+}
+
+// Into converts src into the value pointed by dst using the default Mapper
+func Into(src any, dst any) error {
+	return defaultMapper.Into(src, dst)
+}
+
+// ToMap converts a struct into a map[string]any using the default Mapper
+func ToMap(src any) (map[string]any, error) {
+	return defaultMapper.ToMap(src)
+}
+
+// FromMap converts a map into a new value of type T using the default Mapper
+func FromMap[T any](src map[string]any) (*T, error) {
+	return Convert[map[string]any, T](src)
+}
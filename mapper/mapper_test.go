@@ -0,0 +1,211 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gyozatech/sushi/functional"
+)
+
+type addressDTO struct {
+	Street string
+	City   string
+}
+
+type userDTO struct {
+	ID        string `map:"id"`
+	Name      string
+	Age       string
+	Admin     string
+	Birthday  string `map:"birthday,layout=2006-01-02"`
+	Timeout   string
+	Address   *addressDTO
+	Tags      []string
+	Scores    map[string]string
+	Password  string `map:"-"`
+	Untouched string
+}
+
+type address struct {
+	Street string
+	City   string
+}
+
+type user struct {
+	ID       int64 `map:"id"`
+	Name     *string
+	Age      uint8
+	Admin    bool
+	Birthday time.Time `map:"birthday"`
+	Timeout  time.Duration
+	Address  address
+	Tags     []string
+	Scores   map[string]float64
+	Password string
+}
+
+func TestConvertStructToStruct(t *testing.T) {
+	testName := "TestConvertStructToStruct"
+	dto := userDTO{
+		ID:       "42",
+		Name:     "Alice",
+		Age:      "30",
+		Admin:    "true",
+		Birthday: "1990-05-17",
+		Timeout:  "1m30s",
+		Address:  &addressDTO{Street: "Via Roma", City: "Rome"},
+		Tags:     []string{"a"},
+		Scores:   map[string]string{"math": "9.5"},
+		Password: "secret",
+	}
+
+	actual, err := Convert[userDTO, user](dto)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+	name := "Alice"
+	expected := user{
+		ID:       42,
+		Name:     &name,
+		Age:      30,
+		Admin:    true,
+		Birthday: time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC),
+		Timeout:  90 * time.Second,
+		Address:  address{Street: "Via Roma", City: "Rome"},
+		Tags:     []string{"a"},
+		Scores:   map[string]float64{"math": 9.5},
+	}
+	if !reflect.DeepEqual(expected, *actual) {
+		t.Errorf("%s failed: expected %+v, got %+v", testName, expected, *actual)
+	}
+	actual.Tags[0] = "changed"
+	if dto.Tags[0] != "a" {
+		t.Errorf("%s failed: the slices must be copied", testName)
+	}
+
+	back, err := Convert[user, userDTO](*actual)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+	if back.Birthday != "1990-05-17" || back.ID != "42" || back.Timeout != "1m30s" || back.Address.City != "Rome" || back.Password != "" {
+		t.Errorf("%s failed: wrong reverse conversion %+v", testName, *back)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	testName := "TestConvertErrors"
+
+	testCases := []struct {
+		description  string
+		input        userDTO
+		expectedPath string
+	}{
+		{description: "Invalid number", input: userDTO{Age: "thirty"}, expectedPath: "Age"},
+		{description: "Overflow", input: userDTO{Age: "300"}, expectedPath: "Age"},
+		{description: "Invalid time", input: userDTO{Birthday: "17/05/1990"}, expectedPath: "birthday"},
+		{description: "Invalid nested value", input: userDTO{Scores: map[string]string{"math": "A"}}, expectedPath: "Scores.math"},
+	}
+	for _, testCase := range testCases {
+		_, err := Convert[userDTO, user](testCase.input)
+		var conversionErr *ConversionError
+		if !errors.As(err, &conversionErr) || conversionErr.Path != testCase.expectedPath {
+			t.Errorf("%s failed (%s): expected an error on %s, got %v", testName, testCase.description, testCase.expectedPath, err)
+		}
+	}
+
+	if err := Into(1, user{}); err == nil {
+		t.Errorf("%s failed: expected an error for a non-pointer destination", testName)
+	}
+	var small int8
+	if err := Into(3.5, &small); err == nil {
+		t.Errorf("%s failed: expected an error for a lossy conversion", testName)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	testName := "TestMaps"
+	type item struct {
+		SKU      string    `map:"sku"`
+		Quantity int       `map:"quantity"`
+		Notes    string    `map:"notes,omitempty"`
+		Created  time.Time `map:"created,layout=2006-01-02"`
+	}
+	type order struct {
+		ID    int    `map:"id"`
+		Items []item `map:"items"`
+	}
+
+	m, err := ToMap(order{ID: 1, Items: []item{{SKU: "A", Quantity: 2, Created: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}}})
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+	expected := map[string]any{
+		"id":    1,
+		"items": []any{map[string]any{"sku": "A", "quantity": 2, "created": "2024-01-02"}},
+	}
+	if !reflect.DeepEqual(expected, m) {
+		t.Errorf("%s failed: expected %#v, got %#v", testName, expected, m)
+	}
+
+	// the values decoded from JSON are float64 and the keys may differ in case
+	decoded := map[string]any{"ID": float64(7), "items": []any{map[string]any{"SKU": "B", "quantity": float64(1), "created": "2024-02-03"}}}
+	o, err := FromMap[order](decoded)
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+	if o.ID != 7 || len(o.Items) != 1 || o.Items[0].SKU != "B" || o.Items[0].Created.Day() != 3 {
+		t.Errorf("%s failed: wrong conversion from map %+v", testName, *o)
+	}
+}
+
+func TestCustomConverters(t *testing.T) {
+	testName := "TestCustomConverters"
+	type money struct {
+		Cents int64
+	}
+	type product struct {
+		Price string
+	}
+	type model struct {
+		Price money
+	}
+
+	m := New()
+	RegisterConverter(m, func(s string) (money, error) {
+		var units, cents int64
+		if _, err := fmt.Sscanf(s, "%d.%d", &units, &cents); err != nil {
+			return money{}, err
+		}
+		return money{Cents: units*100 + cents}, nil
+	})
+
+	actual, err := ConvertWith[product, model](m, product{Price: "12.34"})
+	if err != nil || actual.Price.Cents != 1234 {
+		t.Errorf("%s failed: expected 1234 cents, got %+v (%v)", testName, actual, err)
+	}
+	if _, err := ConvertWith[product, model](m, product{Price: "free"}); err == nil || !strings.Contains(err.Error(), "Price") {
+		t.Errorf("%s failed: expected the converter error, got %v", testName, err)
+	}
+}
+
+func TestConvertAsFunction(t *testing.T) {
+	testName := "TestConvertAsFunction"
+	var toModel functional.Function[addressDTO, address] = Convert[addressDTO, address]
+
+	addresses, err := functional.ForEach([]addressDTO{{City: "Rome"}, {City: "Milan"}}, toModel)
+	if err != nil || len(addresses) != 2 || addresses[1].City != "Milan" {
+		t.Errorf("%s failed: wrong conversion %+v (%v)", testName, addresses, err)
+	}
+}
+
+func BenchmarkConvert(b *testing.B) {
+	dto := userDTO{ID: "42", Name: "Alice", Age: "30", Admin: "true", Birthday: "1990-05-17", Address: &addressDTO{City: "Rome"}, Tags: []string{"a", "b"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = Convert[userDTO, user](dto)
+	}
+}
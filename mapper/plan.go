@@ -0,0 +1,128 @@
+package mapper
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldInfo describes an exported struct field as seen by the mapper
+type fieldInfo struct {
+	name      string
+	index     []int
+	typ       reflect.Type
+	omitEmpty bool
+	options   options
+}
+
+// structInfo lists the mappable fields of a struct type
+type structInfo struct {
+	fields []fieldInfo
+	byName map[string]int
+	byFold map[string]int
+}
+
+func (s *structInfo) lookup(name string) (fieldInfo, bool) {
+	if i, ok := s.byName[name]; ok {
+		return s.fields[i], true
+	}
+	if i, ok := s.byFold[strings.ToLower(name)]; ok {
+		return s.fields[i], true
+	}
+	return fieldInfo{}, false
+}
+
+// fieldPlan is the precomputed copy of a field from a source struct to a destination struct
+type fieldPlan struct {
+	name    string
+	src     []int
+	dst     []int
+	dstType reflect.Type
+	options options
+}
+
+type plan struct {
+	fields []fieldPlan
+}
+
+// structInfo returns the cached description of a struct type.
+// The fields are named after their `map` tag, if any, else after their Go name:
+// `map:"-"` skips a field, `map:"name,omitempty"` skips it when converting a zero value into a map,
+// and `map:"name,layout=2006-01-02"` sets the layout to format and parse times.
+func (m *Mapper) structInfo(t reflect.Type) *structInfo {
+	if cached, ok := m.structs.Load(t); ok {
+		return cached.(*structInfo)
+	}
+	info := &structInfo{byName: map[string]int{}, byFold: map[string]int{}}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) || throughPointer(t, field.Index) {
+			continue
+		}
+		tag := field.Tag.Get("map")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := fieldInfo{name: parts[0], index: field.Index, typ: field.Type}
+		if f.name == "" {
+			f.name = field.Name
+		}
+		for _, part := range parts[1:] {
+			switch {
+			case part == "omitempty":
+				f.omitEmpty = true
+			case strings.HasPrefix(part, "layout="):
+				f.options.layout = strings.TrimPrefix(part, "layout=")
+			}
+		}
+		if _, duplicated := info.byName[f.name]; duplicated {
+			continue
+		}
+		info.byName[f.name] = len(info.fields)
+		if _, duplicated := info.byFold[strings.ToLower(f.name)]; !duplicated {
+			info.byFold[strings.ToLower(f.name)] = len(info.fields)
+		}
+		info.fields = append(info.fields, f)
+	}
+	cached, _ := m.structs.LoadOrStore(t, info)
+	return cached.(*structInfo)
+}
+
+// plan returns the cached plan to convert a struct type into another one, matching the fields by name
+func (m *Mapper) plan(src, dst reflect.Type) *plan {
+	key := [2]reflect.Type{src, dst}
+	if cached, ok := m.plans.Load(key); ok {
+		return cached.(*plan)
+	}
+	srcInfo, dstInfo := m.structInfo(src), m.structInfo(dst)
+	p := &plan{}
+	for _, dstField := range dstInfo.fields {
+		srcField, ok := srcInfo.lookup(dstField.name)
+		if !ok {
+			continue
+		}
+		opts := dstField.options
+		if opts.layout == "" {
+			opts.layout = srcField.options.layout
+		}
+		p.fields = append(p.fields, fieldPlan{name: dstField.name, src: srcField.index, dst: dstField.index, dstType: dstField.typ, options: opts})
+	}
+	cached, _ := m.plans.LoadOrStore(key, p)
+	return cached.(*plan)
+}
+
+// throughPointer returns true if the field is promoted from an embedded pointer, which cannot be set safely
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByIndex returns the field of a struct value
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	field, err := v.FieldByIndexErr(index)
+	return field, err == nil
+}
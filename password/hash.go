@@ -0,0 +1,302 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrInvalidHash is returned for the encoded hashes which are not well-formed PHC strings
+	ErrInvalidHash = errors.New("password: invalid hash")
+	// ErrInvalidParameters is returned for the cost parameters out of range, of a Hasher or of an encoded hash
+	ErrInvalidParameters = errors.New("password: invalid parameters")
+	// ErrUnsupportedAlgorithm is returned for the PHC strings of unknown algorithms
+	ErrUnsupportedAlgorithm = errors.New("password: unsupported algorithm")
+)
+
+// Hasher hashes passwords into PHC strings, e.g. $scrypt$ln=15,r=8,p=1$<salt>$<hash>
+type Hasher interface {
+	// Hash returns the PHC string of the password, salted with random bytes
+	Hash(password string) (string, error)
+	// NeedsRehash returns true if the encoded hash uses another algorithm or other parameters than the Hasher:
+	// it should be called after a successful Verify to upgrade the stored hash
+	NeedsRehash(encoded string) bool
+}
+
+// DefaultHasher is the Hasher used by the package-level Hash and NeedsRehash functions
+var DefaultHasher Hasher = Scrypt{}
+
+// Hash hashes the password with the DefaultHasher
+func Hash(password string) (string, error) {
+	return DefaultHasher.Hash(password)
+}
+
+// NeedsRehash tells whether the encoded hash should be replaced by one produced by the DefaultHasher
+func NeedsRehash(encoded string) bool {
+	return DefaultHasher.NeedsRehash(encoded)
+}
+
+// Verify checks the password against a PHC string produced by any of the supported algorithms,
+// using the parameters it contains. The comparison takes constant time.
+func Verify(password, encoded string) (bool, error) {
+	h, err := parsePHC(encoded)
+	if err != nil {
+		return false, err
+	}
+	var key []byte
+	switch h.id {
+	case scryptID:
+		s, err := scryptFromPHC(h)
+		if err != nil {
+			return false, err
+		}
+		if key, err = scrypt.Key([]byte(password), h.salt, 1<<s.LogN, s.R, s.P, len(h.hash)); err != nil {
+			return false, fmt.Errorf("password: %w", err)
+		}
+	case pbkdf2ID:
+		p, err := pbkdf2FromPHC(h)
+		if err != nil {
+			return false, err
+		}
+		key = pbkdf2.Key([]byte(password), h.salt, p.Iterations, len(h.hash), sha256.New)
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, h.id)
+	}
+	return subtle.ConstantTimeCompare(key, h.hash) == 1, nil
+}
+
+const (
+	scryptID = "scrypt"
+	pbkdf2ID = "pbkdf2-sha256"
+
+	defaultSaltLength = 16
+	defaultKeyLength  = 32
+
+	// the bounds of the lengths of the salts and the keys, also of the encoded hashes being verified
+	minSaltLength = 8
+	minKeyLength  = 16
+	maxLength     = 1024
+)
+
+// Scrypt hashes passwords with scrypt (RFC 7914): the zero values of its fields are replaced by the defaults
+// LogN 15, R 8, P 1 (32 MiB of memory per hash), 16 bytes of salt and 32 bytes of key
+type Scrypt struct {
+	// LogN is the base 2 logarithm of the CPU/memory cost N
+	LogN int
+	// R is the block size
+	R int
+	// P is the parallelization
+	P          int
+	SaltLength int
+	KeyLength  int
+}
+
+func (s Scrypt) withDefaults() Scrypt {
+	if s.LogN == 0 {
+		s.LogN = 15
+	}
+	if s.R == 0 {
+		s.R = 8
+	}
+	if s.P == 0 {
+		s.P = 1
+	}
+	if s.SaltLength == 0 {
+		s.SaltLength = defaultSaltLength
+	}
+	if s.KeyLength == 0 {
+		s.KeyLength = defaultKeyLength
+	}
+	return s
+}
+
+// Hash returns the PHC string of the password
+func (s Scrypt) Hash(password string) (string, error) {
+	s = s.withDefaults()
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+	salt, err := randomSalt(s.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<s.LogN, s.R, s.P, s.KeyLength)
+	if err != nil {
+		return "", fmt.Errorf("password: %w", err)
+	}
+	return formatPHC(scryptID, fmt.Sprintf("ln=%d,r=%d,p=%d", s.LogN, s.R, s.P), salt, key), nil
+}
+
+// NeedsRehash returns true if the encoded hash isn't a scrypt hash with the same parameters
+func (s Scrypt) NeedsRehash(encoded string) bool {
+	h, err := parsePHC(encoded)
+	if err != nil || h.id != scryptID {
+		return true
+	}
+	current, err := scryptFromPHC(h)
+	return err != nil || current != s.withDefaults()
+}
+
+func (s Scrypt) validate() error {
+	// the memory needed is 128 * R * N bytes: it's capped at 1 GiB
+	if s.LogN < 1 || s.LogN > 24 || s.R < 1 || s.P < 1 || s.P > 64 || int64(s.R)<<(s.LogN+7) > 1<<30 {
+		return fmt.Errorf("%w: scrypt parameters out of range", ErrInvalidParameters)
+	}
+	return validateLengths(s.SaltLength, s.KeyLength)
+}
+
+func scryptFromPHC(h phc) (Scrypt, error) {
+	params, err := h.intParams("ln", "r", "p")
+	if err != nil {
+		return Scrypt{}, err
+	}
+	s := Scrypt{LogN: params[0], R: params[1], P: params[2], SaltLength: len(h.salt), KeyLength: len(h.hash)}
+	if err := s.validate(); err != nil {
+		return Scrypt{}, fmt.Errorf("%w: %w", ErrInvalidHash, err)
+	}
+	return s, nil
+}
+
+// PBKDF2 hashes passwords with PBKDF2-HMAC-SHA256 (RFC 8018): the zero values of its fields are replaced by the defaults
+// of 600000 iterations, 16 bytes of salt and 32 bytes of key
+type PBKDF2 struct {
+	Iterations int
+	SaltLength int
+	KeyLength  int
+}
+
+func (p PBKDF2) withDefaults() PBKDF2 {
+	if p.Iterations == 0 {
+		p.Iterations = 600000
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = defaultSaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = defaultKeyLength
+	}
+	return p
+}
+
+// Hash returns the PHC string of the password
+func (p PBKDF2) Hash(password string) (string, error) {
+	p = p.withDefaults()
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+	salt, err := randomSalt(p.SaltLength)
+	if err != nil {
+		return "", err
+	}
+	key := pbkdf2.Key([]byte(password), salt, p.Iterations, p.KeyLength, sha256.New)
+	return formatPHC(pbkdf2ID, "i="+strconv.Itoa(p.Iterations), salt, key), nil
+}
+
+// NeedsRehash returns true if the encoded hash isn't a PBKDF2 hash with the same parameters
+func (p PBKDF2) NeedsRehash(encoded string) bool {
+	h, err := parsePHC(encoded)
+	if err != nil || h.id != pbkdf2ID {
+		return true
+	}
+	current, err := pbkdf2FromPHC(h)
+	return err != nil || current != p.withDefaults()
+}
+
+func (p PBKDF2) validate() error {
+	if p.Iterations < 1 || p.Iterations > 100000000 {
+		return fmt.Errorf("%w: PBKDF2 iterations out of range", ErrInvalidParameters)
+	}
+	return validateLengths(p.SaltLength, p.KeyLength)
+}
+
+func pbkdf2FromPHC(h phc) (PBKDF2, error) {
+	params, err := h.intParams("i")
+	if err != nil {
+		return PBKDF2{}, err
+	}
+	p := PBKDF2{Iterations: params[0], SaltLength: len(h.salt), KeyLength: len(h.hash)}
+	if err := p.validate(); err != nil {
+		return PBKDF2{}, fmt.Errorf("%w: %w", ErrInvalidHash, err)
+	}
+	return p, nil
+}
+
+func validateLengths(saltLength, keyLength int) error {
+	if saltLength < minSaltLength || saltLength > maxLength {
+		return fmt.Errorf("%w: salt length %d out of range [%d, %d]", ErrInvalidParameters, saltLength, minSaltLength, maxLength)
+	}
+	if keyLength < minKeyLength || keyLength > maxLength {
+		return fmt.Errorf("%w: key length %d out of range [%d, %d]", ErrInvalidParameters, keyLength, minKeyLength, maxLength)
+	}
+	return nil
+}
+
+func randomSalt(length int) ([]byte, error) {
+	salt := make([]byte, length)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("password: cannot generate the salt: %w", err)
+	}
+	return salt, nil
+}
+
+// phc is a parsed PHC string: $<id>[$v=<version>]$<param>=<value>(,<param>=<value>)*$<salt>$<hash>
+type phc struct {
+	id      string
+	version string
+	params  map[string]string
+	salt    []byte
+	hash    []byte
+}
+
+func formatPHC(id, params string, salt, hash []byte) string {
+	return "$" + id + "$" + params + "$" + base64.RawStdEncoding.EncodeToString(salt) + "$" + base64.RawStdEncoding.EncodeToString(hash)
+}
+
+func parsePHC(encoded string) (phc, error) {
+	parts := strings.Split(encoded, "$")
+	h := phc{params: map[string]string{}}
+	if len(parts) == 6 && strings.HasPrefix(parts[2], "v=") {
+		h.version = strings.TrimPrefix(parts[2], "v=")
+		parts = append(parts[:2], parts[3:]...)
+	}
+	if len(parts) != 5 || parts[0] != "" || parts[1] == "" {
+		return phc{}, fmt.Errorf("%w: expected $id$params$salt$hash", ErrInvalidHash)
+	}
+	h.id = parts[1]
+	for _, param := range strings.Split(parts[2], ",") {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return phc{}, fmt.Errorf("%w: malformed parameter %q", ErrInvalidHash, param)
+		}
+		h.params[name] = value
+	}
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil || len(h.salt) == 0 {
+		return phc{}, fmt.Errorf("%w: malformed salt", ErrInvalidHash)
+	}
+	if h.hash, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil || len(h.hash) == 0 {
+		return phc{}, fmt.Errorf("%w: malformed hash", ErrInvalidHash)
+	}
+	return h, nil
+}
+
+func (h phc) intParams(names ...string) ([]int, error) {
+	values := make([]int, len(names))
+	for i, name := range names {
+		value, err := strconv.Atoi(h.params[name])
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed parameter %s", ErrInvalidHash, name)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
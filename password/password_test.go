@@ -0,0 +1,185 @@
+package password
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+func TestVerifyVectors(t *testing.T) {
+	// test vectors of PBKDF2-HMAC-SHA256 with the inputs of RFC 6070 and of scrypt from RFC 7914, section 12,
+	// encoded as PHC strings: the other vectors of RFC 7914 have salts shorter than the minimum
+	tests := []struct {
+		testName string
+		password string
+		params   string
+		salt     string
+		key      string
+	}{
+		{
+			testName: "pbkdf2 4096 iterations",
+			password: "passwordPASSWORDpassword",
+			params:   "pbkdf2-sha256$i=4096",
+			salt:     "saltSALTsaltSALTsaltSALTsaltSALTsalt",
+			key:      "348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1c635518c7dac47e9",
+		},
+		{
+			testName: "scrypt SodiumChloride",
+			password: "pleaseletmein",
+			params:   "scrypt$ln=14,r=8,p=1",
+			salt:     "SodiumChloride",
+			key:      "7023bdcb3afd7348461c06cd81fd38ebfda8fbba904f8e3ea9b543f6545da1f2d5432955613f0fcf62d49705242a9af9e61e85dc0d651e40dfcf017b45575887",
+		},
+	}
+
+	for _, test := range tests {
+		key, _ := hex.DecodeString(test.key)
+		encoded := "$" + test.params + "$" + base64.RawStdEncoding.EncodeToString([]byte(test.salt)) + "$" + base64.RawStdEncoding.EncodeToString(key)
+		if ok, err := Verify(test.password, encoded); !ok || err != nil {
+			t.Errorf("%s failed (%s): expected the password to match, got %t (%v)", t.Name(), test.testName, ok, err)
+		}
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	hashers := []struct {
+		testName string
+		hasher   Hasher
+		prefix   string
+	}{
+		{testName: "scrypt", hasher: Scrypt{LogN: 10, R: 8, P: 1}, prefix: "$scrypt$ln=10,r=8,p=1$"},
+		{testName: "pbkdf2", hasher: PBKDF2{Iterations: 1000}, prefix: "$pbkdf2-sha256$i=1000$"},
+	}
+
+	for _, test := range hashers {
+		encoded, err := test.hasher.Hash("correct horse battery staple")
+		if err != nil || !strings.HasPrefix(encoded, test.prefix) {
+			t.Fatalf("%s failed (%s): unexpected hash %q (%v)", t.Name(), test.testName, encoded, err)
+		}
+		if other, _ := test.hasher.Hash("correct horse battery staple"); other == encoded {
+			t.Errorf("%s failed (%s): expected a random salt", t.Name(), test.testName)
+		}
+		if ok, err := Verify("correct horse battery staple", encoded); !ok || err != nil {
+			t.Errorf("%s failed (%s): expected the password to match (%v)", t.Name(), test.testName, err)
+		}
+		if ok, err := Verify("Correct horse battery staple", encoded); ok || err != nil {
+			t.Errorf("%s failed (%s): expected a wrong password not to match (%v)", t.Name(), test.testName, err)
+		}
+		if test.hasher.NeedsRehash(encoded) {
+			t.Errorf("%s failed (%s): unexpected rehash", t.Name(), test.testName)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	old, _ := PBKDF2{Iterations: 1000}.Hash("secret")
+	weak, _ := Scrypt{LogN: 10}.Hash("secret")
+	current, _ := Scrypt{LogN: 11}.Hash("secret")
+
+	tests := []struct {
+		testName string
+		encoded  string
+		expected bool
+	}{
+		{testName: "other algorithm", encoded: old, expected: true},
+		{testName: "weaker parameters", encoded: weak, expected: true},
+		{testName: "current parameters", encoded: current, expected: false},
+		{testName: "malformed", encoded: "plain-text", expected: true},
+	}
+	hasher := Scrypt{LogN: 11}
+	for _, test := range tests {
+		if actual := hasher.NeedsRehash(test.encoded); actual != test.expected {
+			t.Errorf("%s failed (%s): expected %t, got %t", t.Name(), test.testName, test.expected, actual)
+		}
+	}
+	if NeedsRehash(current) != true || (PBKDF2{}).NeedsRehash(old) != true || (PBKDF2{Iterations: 1000}).NeedsRehash(old) {
+		t.Errorf("%s failed: wrong comparison with the default parameters", t.Name())
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	tests := []struct {
+		encoded  string
+		expected error
+	}{
+		{encoded: "", expected: ErrInvalidHash},
+		{encoded: "$scrypt$ln=10,r=8,p=1$c2FsdA", expected: ErrInvalidHash},
+		{encoded: "$scrypt$ln=10,r=8$c2FsdA$aGFzaA", expected: ErrInvalidHash},
+		{encoded: "$scrypt$ln=40,r=8,p=1$c2FsdA$aGFzaA", expected: ErrInvalidHash},
+		{encoded: "$pbkdf2-sha256$i=0$c2FsdA$aGFzaA", expected: ErrInvalidHash},
+		{encoded: "$pbkdf2-sha256$i=1$not base64!$aGFzaA", expected: ErrInvalidHash},
+		{encoded: "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA", expected: ErrUnsupportedAlgorithm},
+	}
+	for _, test := range tests {
+		if ok, err := Verify("secret", test.encoded); ok || !errors.Is(err, test.expected) {
+			t.Errorf("%s failed (%q): expected %v, got %t, %v", t.Name(), test.encoded, test.expected, ok, err)
+		}
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	hashers := []Hasher{
+		Scrypt{LogN: 30}, Scrypt{P: 100}, Scrypt{SaltLength: -1}, Scrypt{SaltLength: 4}, Scrypt{KeyLength: 8}, Scrypt{KeyLength: 1 << 20},
+		PBKDF2{Iterations: -1}, PBKDF2{KeyLength: -5}, PBKDF2{SaltLength: 2000}, PBKDF2{KeyLength: 15},
+	}
+	for _, hasher := range hashers {
+		if _, err := hasher.Hash("secret"); !errors.Is(err, ErrInvalidParameters) || errors.Is(err, ErrInvalidHash) {
+			t.Errorf("%s failed (%+v): expected %v, got %v", t.Name(), hasher, ErrInvalidParameters, err)
+		}
+	}
+	salt, key := base64.RawStdEncoding.EncodeToString(make([]byte, 16)), base64.RawStdEncoding.EncodeToString(make([]byte, 32))
+	for _, encoded := range []string{
+		"$scrypt$ln=40,r=8,p=1$" + salt + "$" + key,
+		"$scrypt$ln=10,r=8,p=1$c2FsdA$" + key,
+		"$pbkdf2-sha256$i=1$" + salt + "$aGFzaA",
+		"$pbkdf2-sha256$i=1$" + salt + "$" + base64.RawStdEncoding.EncodeToString(make([]byte, 2000)),
+	} {
+		if _, err := Verify("secret", encoded); !errors.Is(err, ErrInvalidParameters) || !errors.Is(err, ErrInvalidHash) {
+			t.Errorf("%s failed (%s): expected %v for an encoded hash, got %v", t.Name(), encoded, ErrInvalidParameters, err)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		password   string
+		userInputs []string
+		score      int
+		warning    string
+	}{
+		{password: "", score: 0, warning: WarningEmpty},
+		{password: "password", score: 0, warning: WarningCommon},
+		{password: "P@ssw0rd", score: 0, warning: WarningCommon},
+		{password: "Dragon2024", score: 1, warning: WarningDictionary},
+		{password: "poiuytrewq!", score: 0, warning: WarningKeyboard},
+		{password: "aaaaaaaaaaaa", score: 0, warning: WarningRepeated},
+		{password: "abcdefghij12", score: 0, warning: WarningSequence},
+		{password: "alice.smith1990", userInputs: []string{"alice.smith@example.com"}, score: 0, warning: WarningPersonalInput},
+		{password: "xK9#mQ2", score: 2, warning: WarningShort},
+		{password: "v7$Lq9!zR2@wN4kP", score: 4},
+	}
+
+	for _, test := range tests {
+		actual := Estimate(test.password, test.userInputs...)
+		if actual.Score != test.score || (test.warning != "" && !utils.Contains(actual.Warnings, test.warning)) || (test.warning == "" && len(actual.Warnings) > 0) {
+			t.Errorf("%s failed (%q): expected score %d with warning %q, got %+v", t.Name(), test.password, test.score, test.warning, actual)
+		}
+	}
+
+	if weak, strong := Estimate("monkey12"), Estimate("monkey12monkey12zebra"); weak.Entropy >= strong.Entropy {
+		t.Errorf("%s failed: expected longer passwords to be stronger, got %f and %f", t.Name(), weak.Entropy, strong.Entropy)
+	}
+	if len(common) < 300 {
+		t.Errorf("%s failed: expected the embedded list of common passwords, got %d entries", t.Name(), len(common))
+	}
+
+	// the characters after the analysed ones count as random
+	long := strings.Repeat("a", 100000)
+	if strength := Estimate(long); strength.Score != 4 || !utils.Contains(strength.Warnings, WarningRepeated) {
+		t.Errorf("%s failed: unexpected strength of a long password %+v", t.Name(), strength)
+	}
+}
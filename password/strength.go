@@ -0,0 +1,287 @@
+package password
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+//go:embed common.txt
+var commonList string
+
+// common maps the most common passwords to their rank, starting from 1
+var common = func() map[string]int {
+	ranks := map[string]int{}
+	for i, p := range strings.Fields(commonList) {
+		if _, ok := ranks[p]; !ok {
+			ranks[p] = i + 1
+		}
+	}
+	return ranks
+}()
+
+var keyboardRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+// keyboardKeys is the number of keys which can start a keyboard pattern
+const keyboardKeys = 47
+
+var leet = strings.NewReplacer("@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t")
+
+// Warnings reported by Estimate
+const (
+	WarningEmpty         = "the password is empty"
+	WarningShort         = "the password is shorter than 8 characters"
+	WarningCommon        = "the password is one of the most common ones"
+	WarningDictionary    = "the password contains a common password"
+	WarningKeyboard      = "the password contains a keyboard pattern"
+	WarningRepeated      = "the password contains repeated characters"
+	WarningSequence      = "the password contains a sequence of characters"
+	WarningPersonalInput = "the password contains personal information"
+)
+
+// Strength is the result of the estimation of the strength of a password
+type Strength struct {
+	// Entropy is the estimated number of bits an attacker aware of the common patterns has to guess
+	Entropy float64
+	// Score goes from 0 (very weak, below 28 bits) to 4 (very strong, 80 bits or more)
+	Score    int
+	Warnings []string
+}
+
+var scoreThresholds = []float64{28, 36, 60, 80}
+
+// maxAnalysedLength bounds the cost of Estimate on untrusted input: the characters after it count as random ones
+const maxAnalysedLength = 128
+
+// pattern is a guessable part of a password, from start (included) to end (excluded)
+type pattern struct {
+	start, end int
+	bits       float64
+	warning    string
+}
+
+// Estimate estimates the strength of a password: the entropy of its random parts depends on the character classes it uses,
+// while the common passwords (also with l33t substitutions), keyboard patterns, repetitions, sequences
+// and the given user inputs (like the username or the email address) are considered easy to guess.
+// Only the first 128 characters are searched for patterns.
+func Estimate(password string, userInputs ...string) Strength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Strength{Warnings: []string{WarningEmpty}}
+	}
+	poolBits := math.Log2(float64(poolSize(runes)))
+	tail := 0
+	if len(runes) > maxAnalysedLength {
+		tail, runes = len(runes)-maxAnalysedLength, runes[:maxAnalysedLength]
+	}
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+	if rank, ok := dictionaryRank(string(lower)); ok && tail == 0 {
+		return Strength{Entropy: math.Log2(float64(rank + 1)), Warnings: []string{WarningCommon}}
+	}
+
+	patterns := append(repetitions(runes, poolBits), sequences(runes, poolBits)...)
+	patterns = append(patterns, keyboardPatterns(lower)...)
+	patterns = append(patterns, dictionaryWords(runes, lower)...)
+	patterns = append(patterns, personalInputs(lower, userInputs)...)
+
+	// the cheapest cover of the password with patterns and random characters
+	best := make([]float64, len(runes)+1)
+	chosen := make([]*pattern, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] + poolBits
+		for j := range patterns {
+			p := &patterns[j]
+			if p.end == i && best[p.start]+p.bits < best[i] {
+				best[i], chosen[i] = best[p.start]+p.bits, p
+			}
+		}
+	}
+
+	strength := Strength{Entropy: best[len(runes)] + float64(tail)*poolBits, Warnings: []string{}}
+	if len(runes) < 8 {
+		strength.Warnings = append(strength.Warnings, WarningShort)
+	}
+	for i := len(runes); i > 0; {
+		if p := chosen[i]; p != nil {
+			if !utils.Contains(strength.Warnings, p.warning) {
+				strength.Warnings = append(strength.Warnings, p.warning)
+			}
+			i = p.start
+		} else {
+			i--
+		}
+	}
+	for _, threshold := range scoreThresholds {
+		if strength.Entropy >= threshold {
+			strength.Score++
+		}
+	}
+	return strength
+}
+
+// poolSize estimates the number of characters an attacker has to try for each position
+func poolSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	size := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			size += class.size
+		}
+	}
+	return size
+}
+
+// dictionaryRank looks a lowercase word up in the common passwords, also undoing the l33t substitutions
+func dictionaryRank(word string) (int, bool) {
+	if rank, ok := common[word]; ok {
+		return rank, true
+	}
+	rank, ok := common[leet.Replace(word)]
+	return rank, ok
+}
+
+func repetitions(runes []rune, poolBits float64) []pattern {
+	patterns := []pattern{}
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && runes[end] == runes[start] {
+			end++
+		}
+		if end-start >= 3 {
+			patterns = append(patterns, pattern{start: start, end: end, bits: poolBits + math.Log2(float64(end-start)), warning: WarningRepeated})
+		}
+		start = end
+	}
+	return patterns
+}
+
+// sequences finds the runs of consecutive characters, like abc or 987
+func sequences(runes []rune, poolBits float64) []pattern {
+	patterns := []pattern{}
+	for start := 0; start < len(runes)-1; {
+		delta := runes[start+1] - runes[start]
+		end := start + 1
+		for (delta == 1 || delta == -1) && end < len(runes) && runes[end]-runes[end-1] == delta {
+			end++
+		}
+		if end-start >= 3 {
+			patterns = append(patterns, pattern{start: start, end: end, bits: poolBits + math.Log2(float64(end-start)) + 1, warning: WarningSequence})
+			start = end - 1
+		} else {
+			start++
+		}
+	}
+	return patterns
+}
+
+// keyboardPatterns finds the runs of adjacent keys of the same row of a QWERTY keyboard, like qwer or lkjh
+func keyboardPatterns(lower []rune) []pattern {
+	position := func(r rune) (int, int) {
+		for row, keys := range keyboardRows {
+			if column := strings.IndexRune(keys, r); column >= 0 {
+				return row, column
+			}
+		}
+		return -1, -1
+	}
+	patterns := []pattern{}
+	for start := 0; start < len(lower)-1; {
+		row, column := position(lower[start])
+		nextRow, nextColumn := position(lower[start+1])
+		direction := nextColumn - column
+		end := start + 1
+		for row >= 0 && nextRow == row && (direction == 1 || direction == -1) {
+			end++
+			if end == len(lower) {
+				break
+			}
+			column = nextColumn
+			if nextRow, nextColumn = position(lower[end]); nextColumn-column != direction {
+				break
+			}
+		}
+		if end-start >= 3 {
+			patterns = append(patterns, pattern{start: start, end: end, bits: math.Log2(keyboardKeys) + math.Log2(float64(end-start)) + 1, warning: WarningKeyboard})
+			start = end - 1
+		} else {
+			start++
+		}
+	}
+	return patterns
+}
+
+// dictionaryWords finds the common passwords of at least 4 characters contained in the password
+func dictionaryWords(runes, lower []rune) []pattern {
+	patterns := []pattern{}
+	for start := 0; start < len(lower); start++ {
+		for end := start + 4; end <= len(lower) && end-start <= 20; end++ {
+			word := string(lower[start:end])
+			rank, ok := dictionaryRank(word)
+			if !ok {
+				continue
+			}
+			bits := math.Log2(float64(rank + 1))
+			if string(runes[start:end]) != word {
+				bits++ // capitalization
+			}
+			if _, plain := common[word]; !plain {
+				bits++ // l33t substitutions
+			}
+			patterns = append(patterns, pattern{start: start, end: end, bits: bits, warning: WarningDictionary})
+		}
+	}
+	return patterns
+}
+
+// personalInputs finds the user inputs of at least 3 characters contained in the password:
+// the local part of the email addresses is looked up too
+func personalInputs(lower []rune, userInputs []string) []pattern {
+	words := []string{}
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		words = append(words, input)
+		if local, _, ok := strings.Cut(input, "@"); ok {
+			words = append(words, local)
+		}
+	}
+	password := string(lower)
+	patterns := []pattern{}
+	for _, word := range words {
+		if len([]rune(word)) < 3 {
+			continue
+		}
+		for offset := 0; ; {
+			i := strings.Index(password[offset:], word)
+			if i < 0 {
+				break
+			}
+			start := len([]rune(password[:offset+i]))
+			patterns = append(patterns, pattern{start: start, end: start + len([]rune(word)), bits: math.Log2(float64(len(words)+1)) + 1, warning: WarningPersonalInput})
+			offset += i + len(word)
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,109 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gyozatech/sushi/ids"
+	"github.com/gyozatech/sushi/utils"
+)
+
+// DefaultHeader is the HTTP header carrying the request ID unless configured otherwise
+const DefaultHeader = "X-Request-ID"
+
+// maxLength is the maximum length of the request IDs accepted from the clients
+const maxLength = 128
+
+// Key is the context key of the request ID, which FromContext reads
+var Key = utils.NewContextKey[string]("request-id")
+
+// Option customizes the Middleware and the Transport
+type Option func(*config)
+
+type config struct {
+	header   string
+	generate func() string
+}
+
+// WithHeader sets the HTTP header carrying the request ID
+func WithHeader(header string) Option {
+	return func(c *config) {
+		c.header = header
+	}
+}
+
+// WithGenerator sets the function generating the IDs of the requests which don't have one (UUID v7 by default)
+func WithGenerator(generate func() string) Option {
+	return func(c *config) {
+		c.generate = generate
+	}
+}
+
+func newConfig(options []Option) *config {
+	c := &config{header: DefaultHeader, generate: func() string { return ids.NewV7().String() }}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// NewContext returns a child context holding the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return Key.With(ctx, id)
+}
+
+// FromContext returns the request ID held by the context
+func FromContext(ctx context.Context) (string, bool) {
+	return Key.Get(ctx)
+}
+
+// Middleware reads the request ID from the header of the incoming requests, or generates a new one if it's missing or invalid,
+// stores it in the request context and echoes it in the response header.
+// The IDs sent by the clients are accepted only if they are made of at most 128 printable ASCII characters.
+func Middleware(next http.Handler, options ...Option) http.Handler {
+	c := newConfig(options)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(c.header)
+		if !valid(id) {
+			id = c.generate()
+		}
+		w.Header().Set(c.header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// Transport returns an http.RoundTripper which forwards the request ID of the request context in the header of the outgoing
+// requests, unless they already have one, sending them with the base RoundTripper (http.DefaultTransport if nil)
+func Transport(base http.RoundTripper, options ...Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	c := newConfig(options)
+	return roundTripper(func(r *http.Request) (*http.Response, error) {
+		if id, ok := FromContext(r.Context()); ok && r.Header.Get(c.header) == "" {
+			// a RoundTripper must not modify the request
+			r = r.Clone(r.Context())
+			r.Header.Set(c.header, id)
+		}
+		return base.RoundTrip(r)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// valid checks the IDs sent by the clients, which end up in the logs and in the headers of other requests
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
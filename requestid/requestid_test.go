@@ -0,0 +1,128 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gyozatech/sushi/ids"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		testName string
+		header   string
+		expected string
+	}{
+		{"incoming ID", "abc-123", "abc-123"},
+		{"missing ID", "", "generated"},
+		{"ID with spaces", "abc 123", "generated"},
+		{"ID too long", strings.Repeat("a", 129), "generated"},
+	}
+	for _, test := range tests {
+		var fromContext string
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fromContext = Key.MustGet(r.Context())
+		}), WithHeader("X-Correlation-ID"), WithGenerator(func() string { return "generated" }))
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.header != "" {
+			request.Header.Set("X-Correlation-ID", test.header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if fromContext != test.expected {
+			t.Errorf("%s failed (%s): expected %s in the context, got %s", t.Name(), test.testName, test.expected, fromContext)
+		}
+		if echoed := recorder.Header().Get("X-Correlation-ID"); echoed != test.expected {
+			t.Errorf("%s failed (%s): expected %s in the response, got %s", t.Name(), test.testName, test.expected, echoed)
+		}
+	}
+}
+
+func TestMiddlewareDefaultGenerator(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if id, err := ids.ParseUUID(recorder.Header().Get(DefaultHeader)); err != nil || id.Version() != 7 {
+		t.Errorf("%s failed: expected a UUID v7, got %s", t.Name(), recorder.Header().Get(DefaultHeader))
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(DefaultHeader)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	tests := []struct {
+		testName string
+		ctx      context.Context
+		header   string
+		expected string
+	}{
+		{"ID in the context", NewContext(context.Background(), "abc-123"), "", "abc-123"},
+		{"ID in the request", NewContext(context.Background(), "abc-123"), "explicit", "explicit"},
+		{"no ID", context.Background(), "", ""},
+	}
+	for _, test := range tests {
+		request, _ := http.NewRequestWithContext(test.ctx, http.MethodGet, server.URL, nil)
+		if test.header != "" {
+			request.Header.Set(DefaultHeader, test.header)
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			t.Fatalf("%s failed (%s): unexpected error %v", t.Name(), test.testName, err)
+		}
+		response.Body.Close()
+		if received != test.expected {
+			t.Errorf("%s failed (%s): expected %q, got %q", t.Name(), test.testName, test.expected, received)
+		}
+		if test.header == "" && request.Header.Get(DefaultHeader) != "" {
+			t.Errorf("%s failed (%s): the transport modified the original request", t.Name(), test.testName)
+		}
+	}
+}
+
+func TestSlogHelpers(t *testing.T) {
+	ctx := NewContext(context.Background(), "abc-123")
+	var buffer bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+
+	Logger(ctx, base).Info("with logger")
+	base.Info("with attr", Attr(ctx))
+	base.Info("without ID", Attr(context.Background()))
+	logger := slog.New(NewHandler(base.Handler())).With("user", "alice")
+	logger.InfoContext(ctx, "with handler")
+	logger.InfoContext(context.Background(), "with handler without ID")
+	grouped := logger.WithGroup("request").With("method", "GET").WithGroup("body")
+	grouped.InfoContext(ctx, "with groups", "size", 10)
+	grouped.InfoContext(context.Background(), "with groups without ID", "size", 10)
+	logger.WithGroup("empty").InfoContext(ctx, "with empty group")
+
+	expected := `level=INFO msg="with logger" request_id=abc-123
+level=INFO msg="with attr" request_id=abc-123
+level=INFO msg="without ID"
+level=INFO msg="with handler" user=alice request_id=abc-123
+level=INFO msg="with handler without ID" user=alice
+level=INFO msg="with groups" user=alice request.method=GET request.body.size=10 request_id=abc-123
+level=INFO msg="with groups without ID" user=alice request.method=GET request.body.size=10
+level=INFO msg="with empty group" user=alice request_id=abc-123
+`
+	if buffer.String() != expected {
+		t.Errorf("%s failed: expected\n%s\ngot\n%s", t.Name(), expected, buffer.String())
+	}
+}
+
+func dropTime(groups []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		return slog.Attr{}
+	}
+	return a
+}
@@ -0,0 +1,85 @@
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogKey is the name of the request ID attribute of the log records
+const LogKey = "request_id"
+
+// Attr returns the request ID held by the context as a log attribute: an empty one,
+// which slog ignores, if the context doesn't hold it
+func Attr(ctx context.Context) slog.Attr {
+	if id, ok := FromContext(ctx); ok {
+		return slog.String(LogKey, id)
+	}
+	return slog.Attr{}
+}
+
+// Logger returns a logger adding the request ID held by the context to all its records
+func Logger(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	if id, ok := FromContext(ctx); ok {
+		return logger.With(LogKey, id)
+	}
+	return logger
+}
+
+// NewHandler wraps a slog.Handler adding the request ID of the context to the records logged with a context,
+// e.g. with logger.InfoContext(ctx, ...). The request ID is added at the top level even when the logger has groups.
+func NewHandler(handler slog.Handler) slog.Handler {
+	return &logHandler{Handler: handler}
+}
+
+// logHandler passes the attributes to the wrapped handler until the first group: the groups and their
+// attributes are nested in the records by Handle, so that the request ID can be added outside them
+type logHandler struct {
+	slog.Handler
+	groups []logGroup
+}
+
+type logGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	id, ok := FromContext(ctx)
+	if !ok && len(h.groups) == 0 {
+		return h.Handler.Handle(ctx, record)
+	}
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		group := h.groups[i]
+		values := append(append([]slog.Attr{}, group.attrs...), attrs...)
+		attrs = []slog.Attr{{Key: group.name, Value: slog.GroupValue(values...)}}
+	}
+	if ok {
+		attrs = append(attrs, slog.String(LogKey, id))
+	}
+	nested := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	nested.AddAttrs(attrs...)
+	return h.Handler.Handle(ctx, nested)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(h.groups) == 0 {
+		return &logHandler{Handler: h.Handler.WithAttrs(attrs)}
+	}
+	groups := append([]logGroup{}, h.groups...)
+	last := &groups[len(groups)-1]
+	last.attrs = append(append([]slog.Attr{}, last.attrs...), attrs...)
+	return &logHandler{Handler: h.Handler, groups: groups}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	groups := append(append([]logGroup{}, h.groups...), logGroup{name: name})
+	return &logHandler{Handler: h.Handler, groups: groups}
+}
@@ -0,0 +1,233 @@
+// Package assert provides generic test assertions which report the failure and let the test continue
+package assert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// TestingT is the subset of testing.TB used by the assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Equal asserts that two comparable values are equal
+func Equal[T comparable](t TestingT, expected, actual T, msgAndArgs ...any) bool {
+	t.Helper()
+	if expected != actual {
+		return fail(t, "Not equal:", expectedActual(expected, actual), msgAndArgs...)
+	}
+	return true
+}
+
+// NotEqual asserts that two comparable values are not equal
+func NotEqual[T comparable](t TestingT, unexpected, actual T, msgAndArgs ...any) bool {
+	t.Helper()
+	if unexpected == actual {
+		return fail(t, fmt.Sprintf("Should not be equal to %#v", actual), "", msgAndArgs...)
+	}
+	return true
+}
+
+// DeepEqual asserts that two values are structurally equal according to utils.DeepEqual, printing their differences otherwise
+func DeepEqual(t TestingT, expected, actual any, options ...utils.CompareOption) bool {
+	t.Helper()
+	if diffs := utils.Diff(expected, actual, options...); len(diffs) > 0 {
+		return fail(t, "Not deeply equal (- expected, + actual):", differences(diffs))
+	}
+	return true
+}
+
+// JSONEq asserts that two JSON documents are equivalent, regardless of the formatting and of the order of the keys
+func JSONEq(t TestingT, expected, actual string, msgAndArgs ...any) bool {
+	t.Helper()
+	var e, a any
+	if err := json.Unmarshal([]byte(expected), &e); err != nil {
+		return fail(t, fmt.Sprintf("Expected value is not valid JSON: %s", err), "", msgAndArgs...)
+	}
+	if err := json.Unmarshal([]byte(actual), &a); err != nil {
+		return fail(t, fmt.Sprintf("Actual value is not valid JSON: %s", err), "", msgAndArgs...)
+	}
+	if diffs := utils.Diff(e, a); len(diffs) > 0 {
+		return fail(t, "JSON documents are not equivalent (- expected, + actual):", differences(diffs), msgAndArgs...)
+	}
+	return true
+}
+
+// True asserts that a condition is true
+func True(t TestingT, condition bool, msgAndArgs ...any) bool {
+	t.Helper()
+	if !condition {
+		return fail(t, "Should be true", "", msgAndArgs...)
+	}
+	return true
+}
+
+// False asserts that a condition is false
+func False(t TestingT, condition bool, msgAndArgs ...any) bool {
+	t.Helper()
+	if condition {
+		return fail(t, "Should be false", "", msgAndArgs...)
+	}
+	return true
+}
+
+// Nil asserts that a value is nil, including typed nil pointers, slices, maps, channels and functions
+func Nil(t TestingT, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	if !isNil(value) {
+		return fail(t, fmt.Sprintf("Expected nil, found %#v", value), "", msgAndArgs...)
+	}
+	return true
+}
+
+// NotNil asserts that a value is not nil
+func NotNil(t TestingT, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	if isNil(value) {
+		return fail(t, "Expected a value, found nil", "", msgAndArgs...)
+	}
+	return true
+}
+
+// NoError asserts that an error is nil
+func NoError(t TestingT, err error, msgAndArgs ...any) bool {
+	t.Helper()
+	if err != nil {
+		return fail(t, fmt.Sprintf("Unexpected error: %s", err), "", msgAndArgs...)
+	}
+	return true
+}
+
+// Error asserts that an error is not nil
+func Error(t TestingT, err error, msgAndArgs ...any) bool {
+	t.Helper()
+	if err == nil {
+		return fail(t, "Expected an error, found nil", "", msgAndArgs...)
+	}
+	return true
+}
+
+// ErrorIs asserts that an error matches the target according to errors.Is
+func ErrorIs(t TestingT, err, target error, msgAndArgs ...any) bool {
+	t.Helper()
+	if !errors.Is(err, target) {
+		return fail(t, "Error does not match the target:", expectedActual(fmt.Sprint(target), fmt.Sprint(err)), msgAndArgs...)
+	}
+	return true
+}
+
+// ErrorContains asserts that an error is not nil and that its message contains the given text
+func ErrorContains(t TestingT, err error, text string, msgAndArgs ...any) bool {
+	t.Helper()
+	if err == nil || !strings.Contains(err.Error(), text) {
+		return fail(t, fmt.Sprintf("Expected an error containing %q, found %v", text, err), "", msgAndArgs...)
+	}
+	return true
+}
+
+// Contains asserts that a slice contains an element
+func Contains[T comparable](t TestingT, slice []T, element T, msgAndArgs ...any) bool {
+	t.Helper()
+	if !utils.Contains(slice, element) {
+		return fail(t, fmt.Sprintf("%#v does not contain %#v", slice, element), "", msgAndArgs...)
+	}
+	return true
+}
+
+// NotContains asserts that a slice doesn't contain an element
+func NotContains[T comparable](t TestingT, slice []T, element T, msgAndArgs ...any) bool {
+	t.Helper()
+	if utils.Contains(slice, element) {
+		return fail(t, fmt.Sprintf("%#v should not contain %#v", slice, element), "", msgAndArgs...)
+	}
+	return true
+}
+
+// ContainsString asserts that a string contains a substring
+func ContainsString(t TestingT, s, substring string, msgAndArgs ...any) bool {
+	t.Helper()
+	if !strings.Contains(s, substring) {
+		return fail(t, fmt.Sprintf("%q does not contain %q", s, substring), "", msgAndArgs...)
+	}
+	return true
+}
+
+// Len asserts that a slice, map, string, array or channel has the given length
+func Len(t TestingT, value any, length int, msgAndArgs ...any) bool {
+	t.Helper()
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array, reflect.Chan:
+		if v.Len() != length {
+			return fail(t, fmt.Sprintf("Expected length %d, found %d", length, v.Len()), "", msgAndArgs...)
+		}
+		return true
+	}
+	return fail(t, fmt.Sprintf("Cannot get the length of %#v", value), "", msgAndArgs...)
+}
+
+// Eventually asserts that a condition becomes true within the timeout, checking it every tick
+func Eventually(t TestingT, condition func() bool, timeout, tick time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		if condition() {
+			return true
+		}
+		select {
+		case <-deadline.C:
+			return fail(t, fmt.Sprintf("Condition not satisfied within %s", timeout), "", msgAndArgs...)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Panics asserts that a function panics
+func Panics(t TestingT, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	if panicked, _ := didPanic(fn); !panicked {
+		return fail(t, "Function should panic", "", msgAndArgs...)
+	}
+	return true
+}
+
+// NotPanics asserts that a function doesn't panic
+func NotPanics(t TestingT, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	if panicked, value := didPanic(fn); panicked {
+		return fail(t, fmt.Sprintf("Function should not panic, panicked with %v", value), "", msgAndArgs...)
+	}
+	return true
+}
+
+func didPanic(fn func()) (panicked bool, value any) {
+	panicked = true
+	defer func() {
+		value = recover()
+	}()
+	fn()
+	return false, nil
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+	return false
+}
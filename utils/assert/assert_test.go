@@ -0,0 +1,142 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recorder is a TestingT recording the failures instead of reporting them
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	testName := "TestAssertions"
+	Colors = false
+
+	type pair struct {
+		A int
+		B string
+	}
+	var nilPointer *pair
+	counter := 0
+
+	testCases := []struct {
+		description string
+		assertion   func(r *recorder) bool
+		expected    bool
+	}{
+		{"Equal", func(r *recorder) bool { return Equal(r, 1, 1) }, true},
+		{"Equal failing", func(r *recorder) bool { return Equal(r, "a", "b") }, false},
+		{"NotEqual", func(r *recorder) bool { return NotEqual(r, 1, 2) }, true},
+		{"DeepEqual", func(r *recorder) bool { return DeepEqual(r, []pair{{1, "a"}}, []pair{{1, "a"}}) }, true},
+		{"DeepEqual failing", func(r *recorder) bool { return DeepEqual(r, pair{1, "a"}, pair{2, "a"}) }, false},
+		{"JSONEq", func(r *recorder) bool { return JSONEq(r, `{"a": 1, "b": [1, 2]}`, `{"b":[1,2],"a":1}`) }, true},
+		{"JSONEq failing", func(r *recorder) bool { return JSONEq(r, `{"a": 1}`, `{"a": 2}`) }, false},
+		{"JSONEq invalid", func(r *recorder) bool { return JSONEq(r, `{"a": 1}`, `{`) }, false},
+		{"True", func(r *recorder) bool { return True(r, true) }, true},
+		{"False failing", func(r *recorder) bool { return False(r, true) }, false},
+		{"Nil with a typed nil pointer", func(r *recorder) bool { return Nil(r, nilPointer) }, true},
+		{"NotNil failing", func(r *recorder) bool { return NotNil(r, nil) }, false},
+		{"NoError failing", func(r *recorder) bool { return NoError(r, io.EOF) }, false},
+		{"Error", func(r *recorder) bool { return Error(r, io.EOF) }, true},
+		{"ErrorIs", func(r *recorder) bool { return ErrorIs(r, fmt.Errorf("reading: %w", io.EOF), io.EOF) }, true},
+		{"ErrorIs failing", func(r *recorder) bool { return ErrorIs(r, errors.New("EOF"), io.EOF) }, false},
+		{"ErrorContains", func(r *recorder) bool { return ErrorContains(r, io.EOF, "EO") }, true},
+		{"Contains", func(r *recorder) bool { return Contains(r, []string{"a", "b"}, "b") }, true},
+		{"NotContains failing", func(r *recorder) bool { return NotContains(r, []int{1}, 1) }, false},
+		{"ContainsString", func(r *recorder) bool { return ContainsString(r, "sushi", "shi") }, true},
+		{"Len", func(r *recorder) bool { return Len(r, map[string]int{"a": 1}, 1) }, true},
+		{"Len of a number", func(r *recorder) bool { return Len(r, 1, 1) }, false},
+		{"Panics", func(r *recorder) bool { return Panics(r, func() { panic("boom") }) }, true},
+		{"NotPanics failing", func(r *recorder) bool { return NotPanics(r, func() { panic("boom") }) }, false},
+		{"Eventually", func(r *recorder) bool {
+			return Eventually(r, func() bool { counter++; return counter > 2 }, time.Second, time.Millisecond)
+		}, true},
+		{"Eventually failing", func(r *recorder) bool {
+			return Eventually(r, func() bool { return false }, 10*time.Millisecond, time.Millisecond)
+		}, false},
+	}
+	for _, testCase := range testCases {
+		r := &recorder{}
+		actual := testCase.assertion(r)
+		if actual != testCase.expected || (len(r.failures) == 0) != testCase.expected {
+			t.Errorf("%s failed (%s): expected %t, got %t with failures %v", testName, testCase.description, testCase.expected, actual, r.failures)
+		}
+	}
+}
+
+func TestFailureMessages(t *testing.T) {
+	testName := "TestFailureMessages"
+	Colors = false
+
+	testCases := []struct {
+		description string
+		assertion   func(r *recorder)
+		expected    string
+	}{
+		{
+			description: "Equal with a message",
+			assertion:   func(r *recorder) { Equal(r, 1, 2, "user %s", "alice") },
+			expected:    "Not equal:\n- expected: 1\n+ actual:   2\nmessage: user alice",
+		},
+		{
+			description: "DeepEqual",
+			assertion: func(r *recorder) {
+				DeepEqual(r, map[string]any{"a": 1, "b": 2}, map[string]any{"a": 1, "c": 3})
+			},
+			expected: "Not deeply equal (- expected, + actual):\n- /b: 2\n+ /c: 3",
+		},
+		{
+			description: "Multi-line strings",
+			assertion:   func(r *recorder) { Equal(r, "a\nb\nc", "a\nx\nc") },
+			expected:    "Not equal:\n  a\n- b\n+ x\n  c",
+		},
+	}
+	for _, testCase := range testCases {
+		r := &recorder{}
+		testCase.assertion(r)
+		if len(r.failures) != 1 || r.failures[0] != testCase.expected {
+			t.Errorf("%s failed (%s): expected\n%s\ngot\n%s", testName, testCase.description, testCase.expected, strings.Join(r.failures, "\n"))
+		}
+	}
+
+	Colors = true
+	r := &recorder{}
+	Equal(r, 1, 2)
+	if !strings.Contains(r.failures[0], red) || !strings.Contains(r.failures[0], green) {
+		t.Errorf("%s failed: expected a colored message, got %q", testName, r.failures[0])
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	defer reader.Close()
+	defer writer.Close()
+	file, err := os.Create(filepath.Join(t.TempDir(), "output"))
+	if err != nil {
+		t.Fatalf("%s failed: unexpected error %v", t.Name(), err)
+	}
+	defer file.Close()
+
+	for description, f := range map[string]*os.File{"pipe": writer, "file": file} {
+		if isTerminal(f) {
+			t.Errorf("%s failed (%s): expected no terminal", t.Name(), description)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package assert
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// Colors enables the ANSI colors in the failure messages. It defaults to true when the standard output is a terminal
+// and the NO_COLOR environment variable isn't set, so that the CI logs don't get the escape codes.
+var Colors = os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+
+// isTerminal reports whether the file is a terminal (a character device)
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+const (
+	red   = "\033[31m"
+	green = "\033[32m"
+	reset = "\033[0m"
+)
+
+func colored(color, s string) string {
+	if !Colors {
+		return s
+	}
+	return color + s + reset
+}
+
+// fail reports a failure made of a summary, some details and the optional user message
+func fail(t TestingT, summary string, details string, msgAndArgs ...any) bool {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString(summary)
+	if details != "" {
+		b.WriteString("\n")
+		b.WriteString(details)
+	}
+	if msg := message(msgAndArgs...); msg != "" {
+		b.WriteString("\nmessage: ")
+		b.WriteString(msg)
+	}
+	t.Errorf("%s", b.String())
+	return false
+}
+
+func message(msgAndArgs ...any) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+	if format, ok := msgAndArgs[0].(string); ok {
+		if len(msgAndArgs) == 1 {
+			return format
+		}
+		return fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return fmt.Sprintf("%+v", msgAndArgs[0])
+}
+
+// expectedActual formats the two values, with a line by line diff when they are multi-line strings
+func expectedActual(expected, actual any) string {
+	e, eok := expected.(string)
+	a, aok := actual.(string)
+	if eok && aok && (strings.Contains(e, "\n") || strings.Contains(a, "\n")) {
+		return lineDiff(strings.Split(e, "\n"), strings.Split(a, "\n"))
+	}
+	return fmt.Sprintf("%s\n%s",
+		colored(red, fmt.Sprintf("- expected: %#v", expected)),
+		colored(green, fmt.Sprintf("+ actual:   %#v", actual)))
+}
+
+// differences formats the differences found by utils.Diff
+func differences(diffs []utils.Difference) string {
+	lines := []string{}
+	for _, d := range diffs {
+		path := d.Path
+		if path == "" {
+			path = "(root)"
+		}
+		switch d.Kind {
+		case utils.Added:
+			lines = append(lines, colored(green, fmt.Sprintf("+ %s: %#v", path, d.B)))
+		case utils.Removed:
+			lines = append(lines, colored(red, fmt.Sprintf("- %s: %#v", path, d.A)))
+		default:
+			lines = append(lines,
+				colored(red, fmt.Sprintf("- %s: %#v", path, d.A)),
+				colored(green, fmt.Sprintf("+ %s: %#v", path, d.B)))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineDiff returns a unified-like diff of two lists of lines based on their longest common subsequence
+func lineDiff(expected, actual []string) string {
+	lcs := make([][]int, len(expected)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	lines := []string{}
+	i, j := 0, 0
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			lines = append(lines, "  "+expected[i])
+			i++
+			j++
+		case j < len(actual) && (i == len(expected) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, colored(green, "+ "+actual[j]))
+			j++
+		default:
+			lines = append(lines, colored(red, "- "+expected[i]))
+			i++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Cloner is implemented by the types which know how to copy themselves:
+// Clone uses their Clone method instead of copying them field by field.
+// The returned value must be assignable to the type implementing the interface.
+type Cloner interface {
+	Clone() any
+}
+
+var clonerType = reflect.TypeOf((*Cloner)(nil)).Elem()
+
+// Clone deep-copies a value: pointers, slices, maps and interfaces are followed and copied recursively.
+// Aliasing is preserved, so two pointers to the same value are cloned into two pointers to the same copy,
+// and the cyclic structures are cloned without looping forever.
+// The struct fields can be tagged with `clone:"-"` to be left as zero values in the copy,
+// or with `clone:"shallow"` to be copied as they are, sharing what they point to.
+// Unexported fields, channels and functions are always copied as they are.
+func Clone[T any](v T) T {
+	c := cloner{visited: map[visit]reflect.Value{}}
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c.copy(dst, src)
+	return *dst.Addr().Interface().(*T)
+}
+
+type cloner struct {
+	visited map[visit]reflect.Value
+}
+
+func (c *cloner) copy(dst, src reflect.Value) {
+	if src.Kind() != reflect.Interface && src.CanInterface() && src.Type().Implements(clonerType) && !isNilPointer(src) {
+		if clone := reflect.ValueOf(src.Interface().(Cloner).Clone()); clone.IsValid() && clone.Type().AssignableTo(dst.Type()) {
+			dst.Set(clone)
+			return
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := visit{a: src.Pointer(), t: src.Type()}
+		if clone, ok := c.visited[key]; ok {
+			dst.Set(clone)
+			return
+		}
+		clone := reflect.New(src.Type().Elem())
+		c.visited[key] = clone
+		c.copy(clone.Elem(), src.Elem())
+		dst.Set(clone)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		clone := reflect.New(src.Elem().Type()).Elem()
+		c.copy(clone, src.Elem())
+		dst.Set(clone)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		// slices are identified by their first element and their length, so that only identical slices are aliased
+		key := visit{a: src.Pointer(), b: uintptr(src.Len()), t: src.Type()}
+		if clone, ok := c.visited[key]; ok {
+			dst.Set(clone)
+			return
+		}
+		clone := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.visited[key] = clone
+		for i := 0; i < src.Len(); i++ {
+			c.copy(clone.Index(i), src.Index(i))
+		}
+		dst.Set(clone)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := visit{a: src.Pointer(), t: src.Type()}
+		if clone, ok := c.visited[key]; ok {
+			dst.Set(clone)
+			return
+		}
+		clone := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.visited[key] = clone
+		iter := src.MapRange()
+		for iter.Next() {
+			k := reflect.New(src.Type().Key()).Elem()
+			c.copy(k, iter.Key())
+			v := reflect.New(src.Type().Elem()).Elem()
+			c.copy(v, iter.Value())
+			clone.SetMapIndex(k, v)
+		}
+		dst.Set(clone)
+	case reflect.Struct:
+		// the shallow copy takes care of the unexported fields, which cannot be set through reflection
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			field := src.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			switch tag, _, _ := strings.Cut(field.Tag.Get("clone"), ","); tag {
+			case "-":
+				dst.Field(i).Set(reflect.Zero(field.Type))
+			case "shallow":
+			default:
+				c.copy(dst.Field(i), src.Field(i))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
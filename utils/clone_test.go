@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type cloneNode struct {
+	Name     string
+	Parent   *cloneNode
+	Children []*cloneNode
+}
+
+// revision counts how many times it has been cloned through its Clone method
+type revision struct {
+	Number int
+}
+
+func (r revision) Clone() any {
+	return revision{Number: r.Number + 1}
+}
+
+type cloneOrder struct {
+	ID       int
+	Items    []string
+	Meta     map[string]interface{}
+	Revision revision
+	Created  time.Time
+	Owner    *cloneNode `clone:"shallow"`
+	Session  []byte     `clone:"-"`
+	Any      interface{}
+	internal []int
+}
+
+func TestClone(t *testing.T) {
+	testName := "TestClone"
+	owner := &cloneNode{Name: "owner"}
+	original := cloneOrder{
+		ID:       1,
+		Items:    []string{"a", "b"},
+		Meta:     map[string]interface{}{"nested": map[string]interface{}{"k": []int{1}}},
+		Revision: revision{Number: 1},
+		Created:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Owner:    owner,
+		Session:  []byte("secret"),
+		Any:      &cloneNode{Name: "any"},
+		internal: []int{1},
+	}
+
+	clone := Clone(original)
+
+	if clone.ID != 1 || !reflect.DeepEqual(clone.Items, original.Items) || !clone.Created.Equal(original.Created) {
+		t.Errorf("%s failed: the clone differs from the original: %+v", testName, clone)
+	}
+	if clone.Revision.Number != 2 {
+		t.Errorf("%s failed: expected the Cloner to be used, got %+v", testName, clone.Revision)
+	}
+	clone.Items[0] = "changed"
+	clone.Meta["nested"].(map[string]interface{})["k"].([]int)[0] = 2
+	clone.Any.(*cloneNode).Name = "changed"
+	if original.Items[0] != "a" || original.Meta["nested"].(map[string]interface{})["k"].([]int)[0] != 1 || original.Any.(*cloneNode).Name != "any" {
+		t.Errorf("%s failed: modifying the clone must not modify the original", testName)
+	}
+	if clone.Owner != owner {
+		t.Errorf("%s failed: shallow fields must be shared", testName)
+	}
+	if clone.Session != nil {
+		t.Errorf("%s failed: skipped fields must be zero, got %v", testName, clone.Session)
+	}
+	if &clone.internal[0] != &original.internal[0] {
+		t.Errorf("%s failed: unexported fields must be copied as they are", testName)
+	}
+}
+
+func TestCloneCyclesAndAliasing(t *testing.T) {
+	testName := "TestCloneCyclesAndAliasing"
+	root := &cloneNode{Name: "root"}
+	child := &cloneNode{Name: "child", Parent: root}
+	root.Children = []*cloneNode{child, child}
+
+	clone := Clone(root)
+	if clone == root || clone.Children[0] == child {
+		t.Fatalf("%s failed: the nodes must be copied", testName)
+	}
+	if clone.Children[0].Parent != clone {
+		t.Errorf("%s failed: the cycle must point to the cloned root", testName)
+	}
+	if clone.Children[0] != clone.Children[1] {
+		t.Errorf("%s failed: aliased pointers must stay aliased", testName)
+	}
+
+	shared := []int{1, 2}
+	pair := [2][]int{shared, shared}
+	clonedPair := Clone(pair)
+	clonedPair[0][0] = 100
+	if clonedPair[1][0] != 100 || shared[0] != 1 {
+		t.Errorf("%s failed: aliased slices must stay aliased in the clone only", testName)
+	}
+}
+
+func TestCloneNil(t *testing.T) {
+	testName := "TestCloneNil"
+	var node *cloneNode
+	if Clone(node) != nil {
+		t.Errorf("%s failed: expected a nil pointer", testName)
+	}
+	var values map[string]int
+	if Clone(values) != nil {
+		t.Errorf("%s failed: expected a nil map", testName)
+	}
+	var empty interface{}
+	if Clone(empty) != nil {
+		t.Errorf("%s failed: expected a nil interface", testName)
+	}
+}
+
+type benchmarkPayload struct {
+	ID    int               `json:"id"`
+	Name  string            `json:"name"`
+	Tags  []string          `json:"tags"`
+	Attrs map[string]string `json:"attrs"`
+	Items []benchmarkItem   `json:"items"`
+}
+
+type benchmarkItem struct {
+	SKU   string  `json:"sku"`
+	Price float64 `json:"price"`
+}
+
+func newBenchmarkPayload() benchmarkPayload {
+	payload := benchmarkPayload{ID: 1, Name: "payload", Tags: []string{"a", "b", "c"}, Attrs: map[string]string{"x": "1", "y": "2"}}
+	for i := 0; i < 50; i++ {
+		payload.Items = append(payload.Items, benchmarkItem{SKU: "sku", Price: float64(i)})
+	}
+	return payload
+}
+
+func BenchmarkClone(b *testing.B) {
+	payload := newBenchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Clone(payload)
+	}
+}
+
+func BenchmarkCloneJSONRoundTrip(b *testing.B) {
+	payload := newBenchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var clone benchmarkPayload
+		data, _ := json.Marshal(payload)
+		_ = json.Unmarshal(data, &clone)
+	}
+}
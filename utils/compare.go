@@ -0,0 +1,657 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiffKind tells how a value differs between the two compared values
+type DiffKind int
+
+const (
+	// Changed means the value is present on both sides but differs
+	Changed DiffKind = iota
+	// Added means the value is present only in the second compared value
+	Added
+	// Removed means the value is present only in the first compared value
+	Removed
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	}
+	return "changed"
+}
+
+// Difference describes a value which differs between the two compared values
+type Difference struct {
+	// Path is the JSON pointer (RFC 6901) of the value, the empty string being the root
+	Path string
+	Kind DiffKind
+	// A is the value found in the first compared value, nil when Added
+	A interface{}
+	// B is the value found in the second compared value, nil when Removed
+	B interface{}
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	switch d.Kind {
+	case Added:
+		return fmt.Sprintf("%s %s: %v", d.Kind, path, d.B)
+	case Removed:
+		return fmt.Sprintf("%s %s: %v", d.Kind, path, d.A)
+	}
+	if reflect.TypeOf(d.A) != reflect.TypeOf(d.B) {
+		return fmt.Sprintf("%s %s: %v (%T) != %v (%T)", d.Kind, path, d.A, d.A, d.B, d.B)
+	}
+	return fmt.Sprintf("%s %s: %v != %v", d.Kind, path, d.A, d.B)
+}
+
+// CompareOption customizes the comparison performed by Diff and DeepEqual
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	coerce           bool
+	ignoreUnexported bool
+	unordered        bool
+	floatTolerance   float64
+	ignorePaths      [][]string
+}
+
+// WithCoercion compares values regardless of their types, like IsEqual but structurally:
+// numbers of any type are compared by value, strings are parsed when compared to numbers or booleans,
+// pointers are dereferenced and structs can be compared to maps by their JSON field names
+func WithCoercion() CompareOption {
+	return func(o *compareOptions) {
+		o.coerce = true
+	}
+}
+
+// IgnoreUnexported skips the unexported struct fields
+func IgnoreUnexported() CompareOption {
+	return func(o *compareOptions) {
+		o.ignoreUnexported = true
+	}
+}
+
+// UnorderedSlices compares slices and arrays as multisets, regardless of the order of their elements
+func UnorderedSlices() CompareOption {
+	return func(o *compareOptions) {
+		o.unordered = true
+	}
+}
+
+// FloatTolerance considers equal the floating-point numbers whose absolute difference is not greater than epsilon
+func FloatTolerance(epsilon float64) CompareOption {
+	return func(o *compareOptions) {
+		o.floatTolerance = epsilon
+	}
+}
+
+// IgnorePaths skips the values at the given JSON pointers: a "*" segment matches any key or index,
+// for example "/items/*/updatedAt". The leading slash is optional and the malformed pointers match nothing.
+func IgnorePaths(paths ...string) CompareOption {
+	return func(o *compareOptions) {
+		for _, path := range paths {
+			if path != "" && !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			if segments, err := ParsePointer(path); err == nil {
+				o.ignorePaths = append(o.ignorePaths, segments)
+			}
+		}
+	}
+}
+
+// Diff compares two values returning the list of their differences, empty if they are equal.
+// Struct fields are named after their json tag, if any, the fields tagged with `compare:"-"` or `json:"-"` are skipped
+// and the fields of the embedded structs are promoted, like in the JSON representation.
+// Nil and empty slices or maps are considered equal, time.Time values are compared with their Equal method
+// (ignoring the monotonic clock and the location, also in unexported fields) and errors are compared by their message
+// (unless they are held by unexported fields, which are compared structurally).
+func Diff(a, b interface{}, options ...CompareOption) []Difference {
+	c := newComparer(options, false)
+	c.compare(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return c.diffs
+}
+
+// DeepEqual returns true if Diff finds no difference between the two values
+func DeepEqual(a, b interface{}, options ...CompareOption) bool {
+	c := newComparer(options, true)
+	c.compare(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return len(c.diffs) == 0
+}
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+type visit struct {
+	a, b uintptr
+	t    reflect.Type
+}
+
+type comparer struct {
+	options     compareOptions
+	stopAtFirst bool
+	diffs       []Difference
+	visited     map[visit]bool
+}
+
+func newComparer(options []CompareOption, stopAtFirst bool) *comparer {
+	c := &comparer{stopAtFirst: stopAtFirst, visited: map[visit]bool{}}
+	for _, option := range options {
+		option(&c.options)
+	}
+	return c
+}
+
+func (c *comparer) report(path []string, kind DiffKind, a, b reflect.Value) {
+	c.diffs = append(c.diffs, Difference{Path: joinPointer(path), Kind: kind, A: interfaceOf(a), B: interfaceOf(b)})
+}
+
+func (c *comparer) done() bool {
+	return c.stopAtFirst && len(c.diffs) > 0
+}
+
+func (c *comparer) compare(path []string, a, b reflect.Value) {
+	if c.done() || c.ignored(path) {
+		return
+	}
+	for {
+		a, b = unwrapInterface(a), unwrapInterface(b)
+		if equal, ok := compareSpecial(a, b); ok {
+			if !equal {
+				c.report(path, Changed, a, b)
+			}
+			return
+		}
+		if !c.options.coerce || (a.Kind() != reflect.Pointer && b.Kind() != reflect.Pointer) {
+			break
+		}
+		if a.Kind() == reflect.Pointer && b.Kind() == reflect.Pointer && !a.IsNil() && !b.IsNil() && c.seen(a, b) {
+			return
+		}
+		a, b = derefPointer(a), derefPointer(b)
+	}
+
+	if !a.IsValid() || !b.IsValid() {
+		if isNilOrEmpty(a) != isNilOrEmpty(b) {
+			c.report(path, Changed, a, b)
+		}
+		return
+	}
+	if isNilOrEmpty(a) && isNilOrEmpty(b) && a.Type() == b.Type() {
+		return
+	}
+
+	if c.options.coerce {
+		c.compareCoerced(path, a, b)
+		return
+	}
+	if a.Type() != b.Type() {
+		c.report(path, Changed, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				c.report(path, Changed, a, b)
+			}
+			return
+		}
+		if c.seen(a, b) {
+			return
+		}
+		c.compare(path, a.Elem(), b.Elem())
+	case reflect.Slice, reflect.Array:
+		c.compareLists(path, a, b)
+	case reflect.Map, reflect.Struct:
+		if a.Kind() == reflect.Map && c.seen(a, b) {
+			return
+		}
+		c.compareMappings(path, a, b)
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			c.report(path, Changed, a, b)
+		}
+	default:
+		if !c.scalarsEqual(a, b) {
+			c.report(path, Changed, a, b)
+		}
+	}
+}
+
+// compareCoerced compares two non-pointer values which may have different types
+func (c *comparer) compareCoerced(path []string, a, b reflect.Value) {
+	switch {
+	case isList(a) && isList(b):
+		c.compareLists(path, a, b)
+	case isMapping(a) && isMapping(b):
+		if a.Kind() == reflect.Map && b.Kind() == reflect.Map && c.seen(a, b) {
+			return
+		}
+		c.compareMappings(path, a, b)
+	case isScalar(a) && isScalar(b):
+		if !c.scalarsEqual(a, b) {
+			c.report(path, Changed, a, b)
+		}
+	case a.Type() == b.Type() && (a.Kind() == reflect.Func || a.Kind() == reflect.Chan || a.Kind() == reflect.UnsafePointer):
+		if a.Pointer() != b.Pointer() {
+			c.report(path, Changed, a, b)
+		}
+	default:
+		c.report(path, Changed, a, b)
+	}
+}
+
+func (c *comparer) compareLists(path []string, a, b reflect.Value) {
+	if c.options.unordered {
+		c.compareUnordered(path, a, b)
+		return
+	}
+	for i := 0; i < a.Len() && i < b.Len(); i++ {
+		c.compare(appendPath(path, strconv.Itoa(i)), a.Index(i), b.Index(i))
+	}
+	for i := b.Len(); i < a.Len() && !c.done(); i++ {
+		c.report(appendPath(path, strconv.Itoa(i)), Removed, a.Index(i), reflect.Value{})
+	}
+	for i := a.Len(); i < b.Len() && !c.done(); i++ {
+		c.report(appendPath(path, strconv.Itoa(i)), Added, reflect.Value{}, b.Index(i))
+	}
+}
+
+func (c *comparer) compareUnordered(path []string, a, b reflect.Value) {
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len() && !c.done(); i++ {
+		found := false
+		for j := 0; j < b.Len(); j++ {
+			if matched[j] {
+				continue
+			}
+			child := &comparer{options: c.options, stopAtFirst: true, visited: map[visit]bool{}}
+			child.compare(appendPath(path, strconv.Itoa(i)), a.Index(i), b.Index(j))
+			if len(child.diffs) == 0 {
+				matched[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			c.report(appendPath(path, strconv.Itoa(i)), Removed, a.Index(i), reflect.Value{})
+		}
+	}
+	for j := 0; j < b.Len() && !c.done(); j++ {
+		if !matched[j] {
+			c.report(appendPath(path, strconv.Itoa(j)), Added, reflect.Value{}, b.Index(j))
+		}
+	}
+}
+
+type mappingEntry struct {
+	key   string
+	value reflect.Value
+}
+
+func (c *comparer) compareMappings(path []string, a, b reflect.Value) {
+	entriesA, entriesB := c.entries(a), c.entries(b)
+	indexB := make(map[string]int, len(entriesB))
+	for i, entry := range entriesB {
+		indexB[entry.key] = i
+	}
+	found := make([]bool, len(entriesB))
+	for _, entry := range entriesA {
+		if c.done() {
+			return
+		}
+		if i, ok := indexB[entry.key]; ok {
+			found[i] = true
+			c.compare(appendPath(path, entry.key), entry.value, entriesB[i].value)
+		} else if !c.ignored(appendPath(path, entry.key)) {
+			c.report(appendPath(path, entry.key), Removed, entry.value, reflect.Value{})
+		}
+	}
+	for i, entry := range entriesB {
+		if !found[i] && !c.done() && !c.ignored(appendPath(path, entry.key)) {
+			c.report(appendPath(path, entry.key), Added, reflect.Value{}, entry.value)
+		}
+	}
+}
+
+// entries lists the fields of a struct or the entries of a map (sorted by key) identified by their path segment
+func (c *comparer) entries(v reflect.Value) []mappingEntry {
+	entries := []mappingEntry{}
+	if v.Kind() == reflect.Map {
+		for _, key := range v.MapKeys() {
+			entries = append(entries, mappingEntry{key: fmt.Sprint(interfaceOf(derefPointer(unwrapInterface(key)))), value: v.MapIndex(key)})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].key < entries[j].key
+		})
+		return entries
+	}
+	return c.fieldEntries(v)
+}
+
+// fieldEntries lists the fields of a struct like encoding/json: the fields tagged with `json:"-"` are skipped and
+// the ones of the embedded structs are promoted, unless a shallower field has the same name
+func (c *comparer) fieldEntries(v reflect.Value) []mappingEntry {
+	entries := []mappingEntry{}
+	depths := map[string]int{}
+	var walk func(v reflect.Value, depth int)
+	walk = func(v reflect.Value, depth int) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Tag.Get("compare") == "-" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if embedded := derefPointer(v.Field(i)); isEmbeddedStruct(field) {
+				// the fields of a nil embedded pointer are missing, as in the JSON representation
+				if embedded.IsValid() {
+					walk(embedded, depth+1)
+				}
+				continue
+			}
+			if c.options.ignoreUnexported && !field.IsExported() {
+				continue
+			}
+			name := fieldName(field)
+			if d, ok := depths[name]; ok && d <= depth {
+				continue
+			}
+			if _, ok := depths[name]; ok {
+				for j := range entries {
+					if entries[j].key == name {
+						entries = append(entries[:j], entries[j+1:]...)
+						break
+					}
+				}
+			}
+			depths[name] = depth
+			entries = append(entries, mappingEntry{key: name, value: v.Field(i)})
+		}
+	}
+	walk(v, 0)
+	return entries
+}
+
+func (c *comparer) seen(a, b reflect.Value) bool {
+	v := visit{a: a.Pointer(), b: b.Pointer(), t: a.Type()}
+	if c.visited[v] {
+		return true
+	}
+	c.visited[v] = true
+	return false
+}
+
+func (c *comparer) ignored(path []string) bool {
+	for _, pattern := range c.options.ignorePaths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matches := true
+		for i := range pattern {
+			if pattern[i] != "*" && pattern[i] != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *comparer) scalarsEqual(a, b reflect.Value) bool {
+	numericA, numericB := isNumeric(a), isNumeric(b)
+	switch {
+	case numericA && numericB:
+		return c.numbersEqual(toNumber(a), toNumber(b))
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return a.Bool() == b.Bool()
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() == b.String()
+	case a.Kind() == reflect.Complex64 || a.Kind() == reflect.Complex128:
+		return (b.Kind() == reflect.Complex64 || b.Kind() == reflect.Complex128) && a.Complex() == b.Complex()
+	case !c.options.coerce:
+		return false
+	case a.Kind() == reflect.String:
+		return c.stringEquals(a.String(), b)
+	case b.Kind() == reflect.String:
+		return c.stringEquals(b.String(), a)
+	}
+	return false
+}
+
+// stringEquals compares a string with a number or a boolean by parsing it
+func (c *comparer) stringEquals(s string, v reflect.Value) bool {
+	if v.Kind() == reflect.Bool {
+		parsed, err := strconv.ParseBool(s)
+		return err == nil && parsed == v.Bool()
+	}
+	if isNumeric(v) {
+		parsed, ok := parseNumber(s)
+		return ok && c.numbersEqual(parsed, toNumber(v))
+	}
+	return false
+}
+
+// number holds either an exact integer or a floating-point value
+type number struct {
+	integer *big.Int
+	float   float64
+}
+
+func (c *comparer) numbersEqual(a, b number) bool {
+	if a.integer != nil && b.integer != nil {
+		return a.integer.Cmp(b.integer) == 0
+	}
+	if math.IsNaN(a.float) || math.IsNaN(b.float) {
+		return false
+	}
+	if a.integer != nil || b.integer != nil {
+		// compare exactly the integers which cannot be represented as float64
+		if exact(a).Cmp(exact(b)) == 0 {
+			return true
+		}
+	} else if a.float == b.float {
+		return true
+	}
+	return c.options.floatTolerance > 0 && math.Abs(a.float-b.float) <= c.options.floatTolerance
+}
+
+func exact(n number) *big.Float {
+	if n.integer != nil {
+		return new(big.Float).SetInt(n.integer)
+	}
+	return big.NewFloat(n.float)
+}
+
+func toNumber(v reflect.Value) number {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{integer: big.NewInt(v.Int()), float: float64(v.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return number{integer: new(big.Int).SetUint64(v.Uint()), float: float64(v.Uint())}
+	case reflect.Float32:
+		// use the shortest decimal representation, so that float32(3.4) equals 3.4
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(v.Float(), 'g', -1, 32), 64)
+		return number{float: f}
+	}
+	return number{float: v.Float()}
+}
+
+func parseNumber(s string) (number, bool) {
+	s = strings.TrimSpace(s)
+	if i, ok := new(big.Int).SetString(s, 10); ok {
+		f, _ := new(big.Float).SetInt(i).Float64()
+		return number{integer: i, float: f}, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return number{}, false
+	}
+	return number{float: f}, true
+}
+
+// compareSpecial compares the values whose equality is not structural: it returns false as second value if it doesn't apply
+func compareSpecial(a, b reflect.Value) (equal bool, ok bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return false, false
+	}
+	if a.Type() == timeType && b.Type() == timeType {
+		if a.CanInterface() && b.CanInterface() {
+			return a.Interface().(time.Time).Equal(b.Interface().(time.Time)), true
+		}
+		secA, nsecA, okA := timeInstant(a)
+		secB, nsecB, okB := timeInstant(b)
+		return secA == secB && nsecA == nsecB, okA && okB
+	}
+	// the methods of the values of unexported fields cannot be called
+	if !a.CanInterface() || !b.CanInterface() {
+		return false, false
+	}
+	if a.Type().Implements(errorType) && b.Type().Implements(errorType) && !isNilPointer(a) && !isNilPointer(b) {
+		return a.Interface().(error).Error() == b.Interface().(error).Error(), true
+	}
+	return false, false
+}
+
+// timeInstant reads the instant of a time.Time obtained from an unexported field, whose Equal method cannot be called,
+// by decoding its wall and ext fields like the time package does: it returns false if the layout is unknown
+func timeInstant(v reflect.Value) (sec int64, nsec int64, ok bool) {
+	const (
+		hasMonotonic   = 1 << 63
+		nsecShift      = 30
+		wallToInternal = (1884*365 + 1884/4 - 1884/100 + 1884/400) * 24 * 60 * 60
+	)
+	wall, ext := v.FieldByName("wall"), v.FieldByName("ext")
+	if wall.Kind() != reflect.Uint64 || ext.Kind() != reflect.Int64 {
+		return 0, 0, false
+	}
+	w := wall.Uint()
+	nsec = int64(w & (1<<nsecShift - 1))
+	if w&hasMonotonic != 0 {
+		return wallToInternal + int64(w<<1>>(nsecShift+1)), nsec, true
+	}
+	return ext.Int(), nsec, true
+}
+
+func unwrapInterface(v reflect.Value) reflect.Value {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v
+}
+
+func derefPointer(v reflect.Value) reflect.Value {
+	if v.IsValid() && v.Kind() == reflect.Pointer {
+		return v.Elem()
+	}
+	return v
+}
+
+func isNilPointer(v reflect.Value) bool {
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+func isNilOrEmpty(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func isNumeric(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isScalar(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool, reflect.String, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return isNumeric(v)
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func isMapping(v reflect.Value) bool {
+	return v.Kind() == reflect.Map || v.Kind() == reflect.Struct
+}
+
+// fieldName returns the name of a struct field in its JSON representation
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// isEmbeddedStruct tells the embedded structs whose fields are promoted in the JSON representation
+func isEmbeddedStruct(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	t := field.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return field.Anonymous && name == "" && t.Kind() == reflect.Struct
+}
+
+// interfaceOf returns the value held by v, even when it comes from an unexported field
+func interfaceOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return fmt.Sprint(v)
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func appendPath(path []string, segment string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, segment)
+}
+
+func joinPointer(path []string) string {
+	var b strings.Builder
+	for _, segment := range path {
+		b.WriteString("/")
+		b.WriteString(pointerEscaper.Replace(segment))
+	}
+	return b.String()
+}
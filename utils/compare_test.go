@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type customer struct {
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	Tags      []string  `json:"tags"`
+	Address   *address  `json:"address"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Cache     string    `compare:"-"`
+	secret    string
+}
+
+func TestDiff(t *testing.T) {
+	testName := "TestDiff"
+	a := customer{Name: "Alice", Age: 30, Tags: []string{"vip"}, Address: &address{City: "Rome", Zip: "00100"}, secret: "a"}
+	b := customer{Name: "Alice", Age: 31, Tags: []string{"vip", "new"}, Address: &address{City: "Milan", Zip: "00100"}, Cache: "x", secret: "b"}
+
+	expected := []Difference{
+		{Path: "/age", Kind: Changed, A: 30, B: 31},
+		{Path: "/tags/1", Kind: Added, A: nil, B: "new"},
+		{Path: "/address/city", Kind: Changed, A: "Rome", B: "Milan"},
+		{Path: "/secret", Kind: Changed, A: "a", B: "b"},
+	}
+	if actual := Diff(a, b); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, actual)
+	}
+
+	expected = []Difference{{Path: "/tags/1", Kind: Added, A: nil, B: "new"}}
+	if actual := Diff(a, b, IgnoreUnexported(), IgnorePaths("/age", "/address/city")); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s failed (ignoring paths): expected %v, got %v", testName, expected, actual)
+	}
+
+	type shadowed struct {
+		embeddedBase
+		ID int `json:"id"`
+	}
+	expected = []Difference{{Path: "/id", Kind: Changed, A: 2, B: 3}}
+	if actual := Diff(shadowed{embeddedBase{1}, 2}, shadowed{embeddedBase{5}, 3}); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s failed (embedded): expected the shallower field only, got %v", testName, actual)
+	}
+
+	maps := Diff(map[string]interface{}{"a/b": 1, "c": 2}, map[string]interface{}{"a/b": 2, "d": 3})
+	expected = []Difference{
+		{Path: "/a~1b", Kind: Changed, A: 1, B: 2},
+		{Path: "/c", Kind: Removed, A: 2, B: nil},
+		{Path: "/d", Kind: Added, A: nil, B: 3},
+	}
+	if !reflect.DeepEqual(expected, maps) {
+		t.Errorf("%s failed (maps): expected %v, got %v", testName, expected, maps)
+	}
+
+	if actual := Diff(1, int64(1)); len(actual) != 1 || actual[0].String() != "changed (root): 1 (int) != 1 (int64)" {
+		t.Errorf("%s failed: expected a type mismatch without coercion, got %v", testName, actual)
+	}
+}
+
+func TestDeepEqualOptions(t *testing.T) {
+	testName := "TestDeepEqualOptions"
+	now := time.Now()
+	tenth, fifth := 0.1, 0.2
+
+	type node struct {
+		Value int
+		Next  *node
+	}
+	cyclicA, cyclicB := &node{Value: 1}, &node{Value: 1}
+	cyclicA.Next, cyclicB.Next = cyclicA, cyclicB
+	type event struct {
+		Name string
+		at   time.Time
+	}
+	rome := time.FixedZone("CEST", 2*60*60)
+
+	testCases := []struct {
+		description string
+		a           interface{}
+		b           interface{}
+		options     []CompareOption
+		expected    bool
+	}{
+		{description: "Times in different zones", a: now, b: now.UTC().Round(0), expected: true},
+		{description: "Different times", a: now, b: now.Add(time.Second), expected: false},
+		{description: "Unexported times with a monotonic clock", a: event{at: now}, b: event{at: now.Round(0)}, expected: true},
+		{description: "Unexported times in different zones", a: event{at: now.Round(0)}, b: event{at: now.In(rome).Round(0)}, expected: true},
+		{description: "Unexported different times", a: event{at: now}, b: event{at: now.Add(time.Nanosecond)}, expected: false},
+		{description: "Unexported times before year 1885", a: event{at: time.Date(1, 1, 1, 0, 0, 0, 5, time.UTC)}, b: event{at: time.Date(1, 1, 1, 0, 0, 0, 5, time.UTC)}, expected: true},
+		{description: "Nil and empty slices", a: []int(nil), b: []int{}, expected: true},
+		{description: "Nil and empty maps", a: map[string]int{}, b: nil, expected: true},
+		{description: "Errors with the same message", a: errors.New("boom"), b: errors.New("boom"), expected: true},
+		{description: "Ordered slices", a: []int{1, 2, 3}, b: []int{3, 2, 1}, expected: false},
+		{description: "Unordered slices", a: []int{1, 2, 2, 3}, b: []int{2, 3, 2, 1}, options: []CompareOption{UnorderedSlices()}, expected: true},
+		{description: "Unordered slices with different counts", a: []int{1, 1, 2}, b: []int{1, 2, 2}, options: []CompareOption{UnorderedSlices()}, expected: false},
+		{description: "Floats without tolerance", a: tenth + fifth, b: 0.3, expected: false},
+		{description: "Floats with tolerance", a: tenth + fifth, b: 0.3, options: []CompareOption{FloatTolerance(1e-9)}, expected: true},
+		{description: "Cyclic structures", a: cyclicA, b: cyclicB, expected: true},
+		{description: "Wildcard paths", a: []customer{{Name: "A", Age: 1}}, b: []customer{{Name: "A", Age: 2}}, options: []CompareOption{IgnorePaths("/*/age")}, expected: true},
+		{description: "Struct and map with coercion", a: address{City: "Rome", Zip: "00100"}, b: map[string]string{"city": "Rome", "zip": "00100"}, options: []CompareOption{WithCoercion()}, expected: true},
+		{description: "Struct and map without coercion", a: address{City: "Rome"}, b: map[string]string{"city": "Rome", "zip": ""}, expected: false},
+		{description: "Large integers", a: uint64(1<<63 + 1), b: "9223372036854775809", options: []CompareOption{WithCoercion()}, expected: true},
+		{description: "Large integers differing by one", a: int64(1<<62 + 1), b: float64(1 << 62), options: []CompareOption{WithCoercion()}, expected: false},
+		{description: "Number and boolean", a: 1, b: true, options: []CompareOption{WithCoercion()}, expected: false},
+	}
+	for _, testCase := range testCases {
+		if actual := DeepEqual(testCase.a, testCase.b, testCase.options...); actual != testCase.expected {
+			t.Errorf("%s failed (%s): expected %t, found %t", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+}
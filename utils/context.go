@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// define a key for our context value
+type contextKey string
+
+const key contextKey = "context-values"
+
+// contextValues returns the values stored in the context: the map is shared and must never be modified,
+// every enrichment stores a new copy of it (copy-on-write)
+func contextValues(ctx context.Context) map[string]interface{} {
+	values, _ := ctx.Value(key).(map[string]interface{})
+	return values
+}
+
+// withContextValue returns a child context with a copy of the values of ctx plus the given one
+func withContextValue(ctx context.Context, name string, value interface{}) context.Context {
+	parent := contextValues(ctx)
+	values := make(map[string]interface{}, len(parent)+1)
+	for k, v := range parent {
+		values[k] = v
+	}
+	values[name] = value
+	return context.WithValue(ctx, key, values)
+}
+
+// EnrichContextWithValues enriches the current context with a map ok key value pairs,
+// replacing the values previously added. The map is copied: changing it later doesn't affect the context.
+func EnrichContextWithValues(ctx context.Context, values map[string]interface{}) context.Context {
+	copied := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		copied[k] = v
+	}
+	return context.WithValue(ctx, key, copied)
+}
+
+// FetchContextValues returns a snapshot of the "context-values" values added in the current context,
+// e.g. to log them: it's a copy which can be freely modified
+func FetchContextValues(ctx context.Context) map[string]interface{} {
+	parent := contextValues(ctx)
+	values := make(map[string]interface{}, len(parent))
+	for k, v := range parent {
+		values[k] = v
+	}
+	return values
+}
+
+// EnrichContextWithValue enriches the context with a key value pair.
+// The parent context is left untouched, so it's safe to enrich the same context concurrently.
+func EnrichContextWithValue[T any](ctx context.Context, key string, value T) context.Context {
+	return withContextValue(ctx, key, value)
+}
+
+// FetchContextValue return a pointer to the specified value got from the context
+func FetchContextValue[T any](ctx context.Context, key string) *T {
+	v, ok := contextValues(ctx)[key].(T)
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+// ContextKey is a typed context key. Every key returned by NewContextKey is distinct, even if another one has the same
+// name and type, so the keys of different packages never collide: its values are stored with context.WithValue,
+// so they are not "context-values" values read by FetchContextValue and FetchContextValues.
+type ContextKey[T any] struct {
+	id *contextKeyID
+}
+
+// contextKeyID identifies a ContextKey by its address
+type contextKeyID struct {
+	name string
+}
+
+// NewContextKey returns a new key of a context value of type T: the name is used in the error messages only
+func NewContextKey[T any](name string) ContextKey[T] {
+	return ContextKey[T]{id: &contextKeyID{name: name}}
+}
+
+// Name returns the name of the key
+func (k ContextKey[T]) Name() string {
+	return k.id.name
+}
+
+// With returns a child context holding the value, leaving ctx untouched
+func (k ContextKey[T]) With(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, k.id, value)
+}
+
+// Get returns the value of the key, false if the context doesn't hold it
+func (k ContextKey[T]) Get(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(k.id).(T)
+	return value, ok
+}
+
+// MustGet is like Get but panics if the context doesn't hold the value
+func (k ContextKey[T]) MustGet(ctx context.Context) T {
+	value, ok := k.Get(ctx)
+	if !ok {
+		panic(fmt.Sprintf("utils: context value %q of type %s not found", k.id.name, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return value
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestContextKey(t *testing.T) {
+	testName := "TestContextKey"
+	userID := NewContextKey[int]("user-id")
+	roles := NewContextKey[[]string]("roles")
+
+	ctx := userID.With(context.Background(), 42)
+	ctx = roles.With(ctx, []string{"admin"})
+
+	if id, ok := userID.Get(ctx); !ok || id != 42 {
+		t.Errorf("%s failed: expected 42, got %d (%t)", testName, id, ok)
+	}
+	if r := roles.MustGet(ctx); len(r) != 1 || r[0] != "admin" {
+		t.Errorf("%s failed: expected [admin], got %v", testName, r)
+	}
+	if _, ok := NewContextKey[int]("missing").Get(ctx); ok {
+		t.Errorf("%s failed: expected no value for a missing key", testName)
+	}
+	if id := FetchContextValue[int](ctx, "user-id"); id != nil || len(FetchContextValues(ctx)) != 0 {
+		t.Errorf("%s failed: expected the typed values not to be context-values values, got %v", testName, FetchContextValues(ctx))
+	}
+	if name := NewContextKey[string]("name").With(EnrichContextWithValue(ctx, "name", "Alessandro"), "Ale"); *FetchContextValue[string](name, "name") != "Alessandro" {
+		t.Errorf("%s failed: expected the typed value not to override the context-values one", testName)
+	}
+	child := userID.With(ctx, 43)
+	if userID.MustGet(child) != 43 || userID.MustGet(ctx) != 42 {
+		t.Errorf("%s failed: expected the child value to override the parent one only in the child", testName)
+	}
+}
+
+func TestContextKeyCollisions(t *testing.T) {
+	testName := "TestContextKeyCollisions"
+	// keys declared by different packages with the same name
+	intID, stringID, otherIntID := NewContextKey[int]("id"), NewContextKey[string]("id"), NewContextKey[int]("id")
+
+	ctx := stringID.With(intID.With(context.Background(), 42), "abc")
+	if id, ok := intID.Get(ctx); !ok || id != 42 {
+		t.Errorf("%s failed: expected 42, got %d (%t)", testName, id, ok)
+	}
+	if id, ok := stringID.Get(ctx); !ok || id != "abc" {
+		t.Errorf("%s failed: expected abc, got %s (%t)", testName, id, ok)
+	}
+	if _, ok := otherIntID.Get(ctx); ok {
+		t.Errorf("%s failed: expected no value for another key of the same name and type", testName)
+	}
+}
+
+func TestContextKeyMustGetPanics(t *testing.T) {
+	testName := "TestContextKeyMustGetPanics"
+	defer func() {
+		if r := recover(); r == nil || fmt.Sprint(r) != `utils: context value "missing" of type int not found` {
+			t.Errorf("%s failed: unexpected panic %v", testName, r)
+		}
+	}()
+	NewContextKey[int]("missing").MustGet(context.Background())
+}
+
+func TestContextValuesCopyOnWrite(t *testing.T) {
+	testName := "TestContextValuesCopyOnWrite"
+	parent := EnrichContextWithValue(context.Background(), "name", "parent")
+	first := EnrichContextWithValue(parent, "first", 1)
+	second := EnrichContextWithValue(parent, "second", 2)
+
+	if FetchContextValue[int](second, "first") != nil || FetchContextValue[int](first, "second") != nil {
+		t.Errorf("%s failed: sibling contexts see each other's values", testName)
+	}
+	if values := FetchContextValues(parent); len(values) != 1 {
+		t.Errorf("%s failed: expected the parent untouched, got %v", testName, values)
+	}
+
+	snapshot := FetchContextValues(first)
+	snapshot["name"] = "changed"
+	if name := FetchContextValue[string](first, "name"); *name != "parent" {
+		t.Errorf("%s failed: changing the snapshot changed the context value to %s", testName, *name)
+	}
+
+	values := map[string]interface{}{"name": "original"}
+	ctx := EnrichContextWithValues(context.Background(), values)
+	values["name"] = "changed"
+	if name := FetchContextValue[string](ctx, "name"); *name != "original" {
+		t.Errorf("%s failed: changing the map changed the context value to %s", testName, *name)
+	}
+}
+
+func TestContextValuesConcurrentEnrichment(t *testing.T) {
+	testName := "TestContextValuesConcurrentEnrichment"
+	parent := EnrichContextWithValue(context.Background(), "name", "parent")
+	key := NewContextKey[int]("worker")
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := key.With(EnrichContextWithValue(parent, "index", i), i)
+			if key.MustGet(ctx) != i || *FetchContextValue[int](ctx, "index") != i || len(FetchContextValues(ctx)) != 2 {
+				t.Errorf("%s failed: unexpected values %v", testName, FetchContextValues(ctx))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -1,34 +1,34 @@
 package utils
 
 import (
-	"context"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
-// GetBasicToken fetches a basic authorization token from the http request
+// GetBasicToken fetches a basic authorization token from the http request as defined by RFC 7617:
+// the scheme is case-insensitive, the credentials are UTF-8 and the password may contain colons
 func GetBasicToken(r *http.Request) (username, password string, err error) {
 	if r == nil {
 		return "", "", fmt.Errorf("invalid HTTP request")
 	}
-	value := r.Header.Get("Authorization")
-	if strings.HasPrefix(value, "Basic ") || strings.HasPrefix(value, "basic ") {
-		split := strings.Split(value, " ")
-		if len(split) == 2 {
-			decoded, err := base64.StdEncoding.DecodeString(split[1])
-			if err != nil {
-				return "", "", fmt.Errorf("basic Authorization token is malformed")
-			}
-			split = strings.Split(string(decoded), ":")
-			if len(split) == 2 {
-				return split[0], split[1], nil
-			}
-		}
+	scheme, token, _ := strings.Cut(strings.TrimSpace(r.Header.Get("Authorization")), " ")
+	if !strings.EqualFold(scheme, "Basic") || strings.TrimSpace(token) == "" {
+		return "", "", fmt.Errorf("basic Authorization token is missing")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(token))
+	if err != nil || !utf8.Valid(decoded) {
+		return "", "", fmt.Errorf("basic Authorization token is malformed")
+	}
+	// the user-id cannot contain a colon, while the password can
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", fmt.Errorf("basic Authorization token is malformed")
 	}
-	return "", "", fmt.Errorf("basic Authorization token is missing")
+	return username, password, nil
 }
 
 // GetBearerToken fetches a bearer authorization token from the http request
@@ -63,47 +63,3 @@ func ValidateIPv4(ip string, withPrefix bool) bool {
 	}
 	return regexp.MustCompile(ipv4Regex).MatchString(ip)
 }
-
-// define a key for our context value
-type contextKey string
-
-const key contextKey = "context-values"
-
-// EnrichContextWithValues enriches the current context with a map ok key value pairs
-func EnrichContextWithValues(ctx context.Context, values map[string]interface{}) context.Context {
-	return context.WithValue(ctx, key, values)
-}
-
-// FetchContextValues returns the map of "context-values" values added in the current context
-func FetchContextValues(ctx context.Context) map[string]interface{} {
-	values := ctx.Value(key)
-	if values == nil {
-		return map[string]interface{}{}
-	}
-	result, ok := values.(map[string]interface{})
-	if !ok {
-		return map[string]interface{}{}
-	}
-	return result
-}
-
-// EnrichContextWithValue enriches the context with a key value pair
-func EnrichContextWithValue[T any](ctx context.Context, key string, value T) context.Context {
-	values := FetchContextValues(ctx)
-	values[key] = value
-	return EnrichContextWithValues(ctx, values)
-}
-
-// FetchContextValue return a pointer to the specified value got from the context
-func FetchContextValue[T any](ctx context.Context, key string) *T {
-	values := FetchContextValues(ctx)
-	value, ok := values[key]
-	if !ok {
-		return nil
-	}
-	v, ok := value.(T)
-	if !ok {
-		return nil
-	}
-	return &v
-}
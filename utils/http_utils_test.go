@@ -2,9 +2,13 @@ package utils
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestValidateIPv6(t *testing.T) {
@@ -133,6 +137,34 @@ func TestValidateIPv4(t *testing.T) {
 	}
 }
 
+type secretHolder struct {
+	Name   string `json:"name"`
+	Secret string `json:"-"`
+}
+
+type embeddedBase struct {
+	ID int `json:"id"`
+}
+
+type withEmbedded struct {
+	embeddedBase
+	Name string `json:"name"`
+}
+
+type Base struct {
+	Kind string
+}
+
+type withOmitEmpty struct {
+	Name  string `json:"name"`
+	Notes string `json:"notes,omitempty"`
+}
+
+type withEmbeddedPointer struct {
+	*Base
+	Name string `json:"name"`
+}
+
 func TestIsEqual(t *testing.T) {
 	testName := "TestIsEqual"
 
@@ -258,6 +290,48 @@ func TestIsEqual(t *testing.T) {
 			inputB:      []int{1, 2},
 			expected:    false,
 		},
+		{
+			description: "Comparing structs differing only in a field tagged with json:\"-\"",
+			inputA:      secretHolder{Name: "a", Secret: "a"},
+			inputB:      secretHolder{Name: "a", Secret: "b"},
+			expected:    true,
+		},
+		{
+			description: "Comparing a struct with an embedded struct and the equivalent map",
+			inputA:      withEmbedded{embeddedBase: embeddedBase{ID: 1}, Name: "a"},
+			inputB:      map[string]any{"id": 1, "name": "a"},
+			expected:    true,
+		},
+		{
+			description: "Comparing a struct with an embedded pointer and the equivalent map",
+			inputA:      withEmbeddedPointer{Base: &Base{Kind: "k"}, Name: "a"},
+			inputB:      map[string]any{"Kind": "k", "name": "a"},
+			expected:    true,
+		},
+		{
+			description: "Comparing a struct with an embedded struct and a map nesting it",
+			inputA:      withEmbedded{embeddedBase: embeddedBase{ID: 1}, Name: "a"},
+			inputB:      map[string]any{"embeddedBase": map[string]any{"id": 1}, "name": "a"},
+			expected:    false,
+		},
+		{
+			description: "Comparing a struct with an omitted empty field and a map without it",
+			inputA:      withOmitEmpty{Name: "a"},
+			inputB:      map[string]any{"name": "a"},
+			expected:    true,
+		},
+		{
+			description: "Comparing a time and its RFC 3339 representation",
+			inputA:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			inputB:      "2024-05-01T10:00:00Z",
+			expected:    true,
+		},
+		{
+			description: "Comparing a time and another instant",
+			inputA:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			inputB:      "2024-05-01T11:00:00Z",
+			expected:    false,
+		},
 	}
 	for _, test := range testCases {
 		if actual := IsEqual(test.inputA, test.inputB); actual != test.expected {
@@ -302,3 +376,37 @@ func TestEnrichContextWithValue(t *testing.T) {
 		t.Errorf("%s failed: expected 'unesisting' to be nil. Got %v", testName, unexisting)
 	}
 }
+
+func TestGetBasicToken(t *testing.T) {
+	encode := func(credentials string) string {
+		return base64.StdEncoding.EncodeToString([]byte(credentials))
+	}
+	testCases := []struct {
+		description string
+		header      string
+		username    string
+		password    string
+		fails       bool
+	}{
+		{description: "valid token", header: "Basic " + encode("alice:secret"), username: "alice", password: "secret"},
+		{description: "case-insensitive scheme", header: "BASIC " + encode("alice:secret"), username: "alice", password: "secret"},
+		{description: "password with colons", header: "Basic " + encode("alice:s3:cr:et"), username: "alice", password: "s3:cr:et"},
+		{description: "empty password", header: "basic " + encode("alice:"), username: "alice", password: ""},
+		{description: "UTF-8 credentials", header: "Basic " + encode("test:123£"), username: "test", password: "123£"},
+		{description: "missing header", header: "", fails: true},
+		{description: "other scheme", header: "Bearer " + encode("alice:secret"), fails: true},
+		{description: "missing colon", header: "Basic " + encode("alice"), fails: true},
+		{description: "invalid base64", header: "Basic !!!", fails: true},
+		{description: "invalid UTF-8", header: "Basic " + encode("alice:\xff\xfe"), fails: true},
+	}
+	for _, test := range testCases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.header != "" {
+			r.Header.Set("Authorization", test.header)
+		}
+		username, password, err := GetBasicToken(r)
+		if test.fails != (err != nil) || username != test.username || password != test.password {
+			t.Errorf("%s failed (%s): expected %q %q (fails: %t), got %q %q %v", t.Name(), test.description, test.username, test.password, test.fails, username, password, err)
+		}
+	}
+}
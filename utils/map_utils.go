@@ -0,0 +1,190 @@
+package utils
+
+import "sort"
+
+// Keys returns the keys of a map in ascending order
+func Keys[K Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// Values returns the values of a map ordered by their keys
+func Values[K Ordered, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, k := range Keys(m) {
+		values = append(values, m[k])
+	}
+	return values
+}
+
+// MergeStrategy resolves the conflict when the same key is found in more than one of the maps being merged
+type MergeStrategy[K comparable, V any] func(key K, existing, incoming V) V
+
+// KeepFirst is the MergeStrategy keeping the value found first
+func KeepFirst[K comparable, V any]() MergeStrategy[K, V] {
+	return func(key K, existing, incoming V) V {
+		return existing
+	}
+}
+
+// KeepLast is the MergeStrategy keeping the value found last
+func KeepLast[K comparable, V any]() MergeStrategy[K, V] {
+	return func(key K, existing, incoming V) V {
+		return incoming
+	}
+}
+
+// MergeDeep is the MergeStrategy merging recursively the nested map[string]interface{} values:
+// any other conflicting value is resolved keeping the last one.
+// The inputs are never modified and the merged maps share no nested map with them, while the values
+// of the keys found in one of the maps only are shared, since Merge calls the strategy on the conflicts only.
+func MergeDeep[K comparable]() MergeStrategy[K, interface{}] {
+	return func(key K, existing, incoming interface{}) interface{} {
+		return mergeDeep(existing, incoming)
+	}
+}
+
+func mergeDeep(existing, incoming interface{}) interface{} {
+	existingMap, ok := existing.(map[string]interface{})
+	if !ok {
+		return incoming
+	}
+	incomingMap, ok := incoming.(map[string]interface{})
+	if !ok {
+		return incoming
+	}
+	result := make(map[string]interface{}, len(existingMap)+len(incomingMap))
+	for k, v := range existingMap {
+		result[k] = cloneDeep(v)
+	}
+	for k, v := range incomingMap {
+		if current, ok := result[k]; ok {
+			result[k] = mergeDeep(current, v)
+		} else {
+			result[k] = cloneDeep(v)
+		}
+	}
+	return result
+}
+
+// cloneDeep copies recursively the map[string]interface{} values
+func cloneDeep(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	clone := make(map[string]interface{}, len(m))
+	for k, nested := range m {
+		clone[k] = cloneDeep(nested)
+	}
+	return clone
+}
+
+// Merge merges the maps into a new one, resolving the conflicting keys with the given strategy (KeepLast if nil)
+func Merge[K comparable, V any](strategy MergeStrategy[K, V], maps ...map[K]V) map[K]V {
+	if strategy == nil {
+		strategy = KeepLast[K, V]()
+	}
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			if existing, ok := result[k]; ok {
+				result[k] = strategy(k, existing, v)
+			} else {
+				result[k] = v
+			}
+		}
+	}
+	return result
+}
+
+// Invert swaps the keys and the values of a map: if a value is duplicated, one of its keys is kept arbitrarily
+func Invert[K comparable, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+	for k, v := range m {
+		result[v] = k
+	}
+	return result
+}
+
+// FilterMap returns a new map with the entries which verify a given condition
+func FilterMap[K comparable, V any](m map[K]V, where func(k K, v V) bool) map[K]V {
+	result := map[K]V{}
+	for k, v := range m {
+		if where(k, v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// MapValues returns a new map with the same keys and the transformed values
+func MapValues[K comparable, V any, W any](m map[K]V, fn func(v V) W) map[K]W {
+	result := make(map[K]W, len(m))
+	for k, v := range m {
+		result[k] = fn(v)
+	}
+	return result
+}
+
+// MapKeys returns a new map with the transformed keys: if two keys are transformed into the same one, one of their values is kept arbitrarily
+func MapKeys[K comparable, J comparable, V any](m map[K]V, fn func(k K) J) map[J]V {
+	result := make(map[J]V, len(m))
+	for k, v := range m {
+		result[fn(k)] = v
+	}
+	return result
+}
+
+// Pick returns a new map with only the given keys
+func Pick[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	result := make(map[K]V, len(keys))
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Omit returns a new map without the given keys
+func Omit[K comparable, V any](m map[K]V, keys ...K) map[K]V {
+	omitted := NewSet(keys...)
+	return FilterMap(m, func(k K, v V) bool {
+		return !omitted.Has(k)
+	})
+}
+
+// Entry is a key value pair of a map
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Entries converts a map into a slice of entries ordered by key
+func Entries[K Ordered, V any](m map[K]V) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for _, k := range Keys(m) {
+		entries = append(entries, Entry[K, V]{Key: k, Value: m[k]})
+	}
+	return entries
+}
+
+// FromEntries converts a slice of entries into a map: if a key is duplicated, the last entry wins
+func FromEntries[K comparable, V any](entries []Entry[K, V]) map[K]V {
+	result := make(map[K]V, len(entries))
+	for _, entry := range entries {
+		result[entry.Key] = entry.Value
+	}
+	return result
+}
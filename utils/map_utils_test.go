@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeysAndValues(t *testing.T) {
+	testName := "TestKeysAndValues"
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(expected, Keys(m)) {
+		t.Errorf("%s failed: expected keys %v, got %v", testName, expected, Keys(m))
+	}
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(expected, Values(m)) {
+		t.Errorf("%s failed: expected values %v, got %v", testName, expected, Values(m))
+	}
+	entries := Entries(m)
+	if expected := (Entry[string, int]{Key: "a", Value: 1}); entries[0] != expected {
+		t.Errorf("%s failed: expected first entry %v, got %v", testName, expected, entries[0])
+	}
+	if back := FromEntries(entries); !reflect.DeepEqual(m, back) {
+		t.Errorf("%s failed: expected %v, got %v", testName, m, back)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	testName := "TestMerge"
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 20, "z": 30}
+
+	testCases := []struct {
+		description string
+		strategy    MergeStrategy[string, int]
+		expected    map[string]int
+	}{
+		{description: "Default strategy", strategy: nil, expected: map[string]int{"x": 1, "y": 20, "z": 30}},
+		{description: "KeepFirst", strategy: KeepFirst[string, int](), expected: map[string]int{"x": 1, "y": 2, "z": 30}},
+		{description: "KeepLast", strategy: KeepLast[string, int](), expected: map[string]int{"x": 1, "y": 20, "z": 30}},
+		{
+			description: "Custom resolver",
+			strategy:    func(key string, existing, incoming int) int { return existing + incoming },
+			expected:    map[string]int{"x": 1, "y": 22, "z": 30},
+		},
+	}
+	for _, testCase := range testCases {
+		if actual := Merge(testCase.strategy, a, b); !reflect.DeepEqual(testCase.expected, actual) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+}
+
+func TestMergeDeep(t *testing.T) {
+	testName := "TestMergeDeep"
+	defaults := map[string]interface{}{
+		"db":    map[string]interface{}{"host": "localhost", "port": 5432, "pool": map[string]interface{}{"size": 10}},
+		"debug": false,
+	}
+	overrides := map[string]interface{}{
+		"db":    map[string]interface{}{"host": "db.internal", "tls": map[string]interface{}{"enabled": true}},
+		"debug": true,
+	}
+
+	merged := Merge(MergeDeep[string](), defaults, overrides)
+	expected := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "db.internal",
+			"port": 5432,
+			"pool": map[string]interface{}{"size": 10},
+			"tls":  map[string]interface{}{"enabled": true},
+		},
+		"debug": true,
+	}
+	if !reflect.DeepEqual(expected, merged) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, merged)
+	}
+	if defaults["db"].(map[string]interface{})["host"] != "localhost" {
+		t.Errorf("%s failed: the inputs must not be modified", testName)
+	}
+
+	// the nested maps without conflicts are copied too
+	db := merged["db"].(map[string]interface{})
+	db["pool"].(map[string]interface{})["size"] = 20
+	db["tls"].(map[string]interface{})["enabled"] = false
+	if defaults["db"].(map[string]interface{})["pool"].(map[string]interface{})["size"] != 10 ||
+		overrides["db"].(map[string]interface{})["tls"].(map[string]interface{})["enabled"] != true {
+		t.Errorf("%s failed: the merged map must not share the nested maps of the inputs", testName)
+	}
+}
+
+func TestMapTransformations(t *testing.T) {
+	testName := "TestMapTransformations"
+	m := map[string]int{"one": 1, "two": 2, "three": 3}
+
+	if expected := map[int]string{1: "one", 2: "two", 3: "three"}; !reflect.DeepEqual(expected, Invert(m)) {
+		t.Errorf("%s failed (Invert): expected %v, got %v", testName, expected, Invert(m))
+	}
+	odd := FilterMap(m, func(k string, v int) bool { return v%2 == 1 })
+	if expected := map[string]int{"one": 1, "three": 3}; !reflect.DeepEqual(expected, odd) {
+		t.Errorf("%s failed (FilterMap): expected %v, got %v", testName, expected, odd)
+	}
+	doubled := MapValues(m, func(v int) int { return v * 2 })
+	if expected := map[string]int{"one": 2, "two": 4, "three": 6}; !reflect.DeepEqual(expected, doubled) {
+		t.Errorf("%s failed (MapValues): expected %v, got %v", testName, expected, doubled)
+	}
+	upper := MapKeys(m, strings.ToUpper)
+	if expected := map[string]int{"ONE": 1, "TWO": 2, "THREE": 3}; !reflect.DeepEqual(expected, upper) {
+		t.Errorf("%s failed (MapKeys): expected %v, got %v", testName, expected, upper)
+	}
+	if expected := map[string]int{"one": 1}; !reflect.DeepEqual(expected, Pick(m, "one", "four")) {
+		t.Errorf("%s failed (Pick): expected %v, got %v", testName, expected, Pick(m, "one", "four"))
+	}
+	if expected := map[string]int{"two": 2}; !reflect.DeepEqual(expected, Omit(m, "one", "three")) {
+		t.Errorf("%s failed (Omit): expected %v, got %v", testName, expected, Omit(m, "one", "three"))
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPointer is returned for the malformed JSON pointers
+var ErrInvalidPointer = errors.New("invalid JSON pointer")
+
+// ParsePointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens: the empty pointer refers
+// to the whole document and has no tokens
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: %q doesn't start with /", ErrInvalidPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1')) {
+				return nil, fmt.Errorf("%w: invalid escape in %q", ErrInvalidPointer, pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
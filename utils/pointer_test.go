@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		testName string
+		pointer  string
+		expected []string
+		err      error
+	}{
+		{testName: "whole document", pointer: "", expected: []string{}},
+		{testName: "root key", pointer: "/", expected: []string{""}},
+		{testName: "nested", pointer: "/a/0/b", expected: []string{"a", "0", "b"}},
+		{testName: "escapes", pointer: "/a~1b/m~0n", expected: []string{"a/b", "m~n"}},
+		{testName: "no leading slash", pointer: "a", err: ErrInvalidPointer},
+		{testName: "invalid escape", pointer: "/a~2", err: ErrInvalidPointer},
+		{testName: "trailing tilde", pointer: "/a~", err: ErrInvalidPointer},
+	}
+	for _, test := range tests {
+		tokens, err := ParsePointer(test.pointer)
+		if !errors.Is(err, test.err) || (test.err == nil && !reflect.DeepEqual(tokens, test.expected)) {
+			t.Errorf("%s failed (%s): expected %v (%v), got %v (%v)", t.Name(), test.testName, test.expected, test.err, tokens, err)
+		}
+	}
+}
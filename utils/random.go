@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// LowerLetters is the set of the lowercase ASCII letters
+	LowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	// UpperLetters is the set of the uppercase ASCII letters
+	UpperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// Digits is the set of the decimal digits
+	Digits = "0123456789"
+	// Symbols is the set of the symbols used in passwords by default
+	Symbols = "!@#$%^&*()-_=+[]{};:',.<>?/"
+	// AmbiguousCharacters are the characters easily mistaken for one another
+	AmbiguousCharacters = "Il1|O0o'`"
+)
+
+// RandomString generates a random alphanumeric string of the given length using crypto/rand
+func RandomString(length int) string {
+	return RandomStringFrom(LowerLetters+UpperLetters+Digits, length)
+}
+
+// RandomPassword generates a random password of the given length using crypto/rand.
+// Passwords of at least 4 characters contain at least an uppercase letter, a lowercase letter, a digit and a symbol.
+func RandomPassword(length int) string {
+	if length <= 0 {
+		return ""
+	}
+	policy := PasswordPolicy{Length: length}
+	if length >= 4 {
+		policy.MinUpper, policy.MinLower, policy.MinDigits, policy.MinSymbols = 1, 1, 1, 1
+	}
+	password, err := policy.Generate()
+	if err != nil {
+		panic(err)
+	}
+	return password
+}
+
+// RandomStringFrom generates a random string of the given length picking its characters from the charset
+// with crypto/rand, each character being equally likely. It panics if the system random source fails.
+func RandomStringFrom(charset string, length int) string {
+	characters := []rune(charset)
+	str := make([]rune, length)
+	for i := range str {
+		str[i] = characters[randomIndex(len(characters))]
+	}
+	return string(str)
+}
+
+// Entropy estimates the entropy in bits of a random string of the given length,
+// whose characters are picked uniformly from the charset
+func Entropy(charset string, length int) float64 {
+	distinct := NewSet([]rune(charset)...).Len()
+	if distinct == 0 || length <= 0 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(distinct))
+}
+
+// randomIndex returns a uniformly distributed random number in [0, n) read from crypto/rand:
+// the values which would make the modulo biased are rejected
+func randomIndex(n int) int {
+	if n <= 0 {
+		panic("utils: random index of an empty range")
+	}
+	bound := uint64(n)
+	limit := math.MaxUint64 - math.MaxUint64%bound
+	var buf [8]byte
+	for {
+		if _, err := rand.Read(buf[:]); err != nil {
+			panic(fmt.Sprintf("utils: cannot read random bytes: %v", err))
+		}
+		if v := binary.LittleEndian.Uint64(buf[:]); v < limit {
+			return int(v % bound)
+		}
+	}
+}
+
+// PasswordPolicy describes the passwords generated by Generate
+type PasswordPolicy struct {
+	Length     int
+	MinUpper   int
+	MinLower   int
+	MinDigits  int
+	MinSymbols int
+	// Symbols replaces the default set of symbols when not empty
+	Symbols string
+	// ExcludeAmbiguous removes the AmbiguousCharacters from all the character classes
+	ExcludeAmbiguous bool
+}
+
+// ErrInvalidPolicy is returned when no password can satisfy a PasswordPolicy
+var ErrInvalidPolicy = errors.New("invalid password policy")
+
+// classes returns the character sets of the policy along with their minimum number of characters
+func (p PasswordPolicy) classes() ([]string, []int) {
+	symbols := p.Symbols
+	if symbols == "" {
+		symbols = Symbols
+	}
+	classes := []string{UpperLetters, LowerLetters, Digits, symbols}
+	if p.ExcludeAmbiguous {
+		for i, class := range classes {
+			classes[i] = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(AmbiguousCharacters, r) {
+					return -1
+				}
+				return r
+			}, class)
+		}
+	}
+	return classes, []int{p.MinUpper, p.MinLower, p.MinDigits, p.MinSymbols}
+}
+
+// Charset returns all the characters a password can be made of
+func (p PasswordPolicy) Charset() string {
+	classes, _ := p.classes()
+	return strings.Join(classes, "")
+}
+
+// Validate checks that the policy can be satisfied
+func (p PasswordPolicy) Validate() error {
+	classes, minimums := p.classes()
+	required := 0
+	for i, minimum := range minimums {
+		if minimum < 0 {
+			return fmt.Errorf("%w: negative minimum", ErrInvalidPolicy)
+		}
+		if minimum > 0 && classes[i] == "" {
+			return fmt.Errorf("%w: a required character class is empty", ErrInvalidPolicy)
+		}
+		required += minimum
+	}
+	if p.Length <= 0 || required > p.Length {
+		return fmt.Errorf("%w: length %d cannot fit %d required characters", ErrInvalidPolicy, p.Length, required)
+	}
+	return nil
+}
+
+// Generate returns a random password satisfying the policy, using crypto/rand
+func (p PasswordPolicy) Generate() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	classes, minimums := p.classes()
+	password := make([]rune, 0, p.Length)
+	for i, minimum := range minimums {
+		password = append(password, []rune(RandomStringFrom(classes[i], minimum))...)
+	}
+	password = append(password, []rune(RandomStringFrom(p.Charset(), p.Length-len(password)))...)
+	// Fisher-Yates shuffle, so that the required characters can be anywhere
+	for i := len(password) - 1; i > 0; i-- {
+		j := randomIndex(i + 1)
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// Entropy estimates the entropy in bits of the passwords generated by the policy,
+// as if all their characters were picked from the whole charset
+func (p PasswordPolicy) Entropy() float64 {
+	return Entropy(p.Charset(), p.Length)
+}
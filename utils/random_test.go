@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestRandomStringFrom(t *testing.T) {
+	testName := "TestRandomStringFrom"
+
+	if s := RandomString(32); len(s) != 32 || strings.Trim(s, LowerLetters+UpperLetters+Digits) != "" {
+		t.Errorf("%s failed (RandomString): unexpected string %q", testName, s)
+	}
+	if s := RandomStringFrom("αβγ", 10); len([]rune(s)) != 10 || strings.Trim(s, "αβγ") != "" {
+		t.Errorf("%s failed (unicode charset): unexpected string %q", testName, s)
+	}
+	if RandomString(16) == RandomString(16) {
+		t.Errorf("%s failed: two random strings are equal", testName)
+	}
+
+	// every character of the charset is picked with about the same frequency
+	counts := map[rune]int{}
+	for _, r := range RandomStringFrom("abc", 30000) {
+		counts[r]++
+	}
+	for _, r := range "abc" {
+		if counts[r] < 9000 || counts[r] > 11000 {
+			t.Errorf("%s failed (distribution): %c picked %d times out of 30000", testName, r, counts[r])
+		}
+	}
+}
+
+func TestRandomPassword(t *testing.T) {
+	testName := "TestRandomPassword"
+	for i := 0; i < 100; i++ {
+		password := RandomPassword(4)
+		if len(password) != 4 ||
+			!strings.ContainsAny(password, UpperLetters) || !strings.ContainsAny(password, LowerLetters) ||
+			!strings.ContainsAny(password, Digits) || !strings.ContainsAny(password, Symbols) {
+			t.Fatalf("%s failed: %q doesn't contain every character class", testName, password)
+		}
+	}
+	if password := RandomPassword(0); password != "" {
+		t.Errorf("%s failed: expected an empty password, got %q", testName, password)
+	}
+	if password := RandomPassword(2); len(password) != 2 {
+		t.Errorf("%s failed: expected a short password, got %q", testName, password)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	testName := "TestPasswordPolicy"
+	policy := PasswordPolicy{Length: 20, MinUpper: 3, MinLower: 2, MinDigits: 4, MinSymbols: 5, Symbols: "#!", ExcludeAmbiguous: true}
+	for i := 0; i < 50; i++ {
+		password, err := policy.Generate()
+		if err != nil {
+			t.Fatalf("%s failed: unexpected error %v", testName, err)
+		}
+		var upper, lower, digits, symbols int
+		for _, r := range password {
+			switch {
+			case unicode.IsUpper(r):
+				upper++
+			case unicode.IsLower(r):
+				lower++
+			case unicode.IsDigit(r):
+				digits++
+			case r == '#' || r == '!':
+				symbols++
+			default:
+				t.Fatalf("%s failed: unexpected character %c in %q", testName, r, password)
+			}
+		}
+		if len(password) != 20 || upper < 3 || lower < 2 || digits < 4 || symbols < 5 || strings.ContainsAny(password, AmbiguousCharacters) {
+			t.Fatalf("%s failed: %q doesn't satisfy the policy", testName, password)
+		}
+	}
+
+	invalid := []PasswordPolicy{
+		{Length: 0},
+		{Length: 3, MinUpper: 2, MinDigits: 2},
+		{Length: 8, MinLower: -1},
+		{Length: 8, MinSymbols: 1, Symbols: "|", ExcludeAmbiguous: true},
+	}
+	for _, p := range invalid {
+		if _, err := p.Generate(); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("%s failed (%+v): expected ErrInvalidPolicy, got %v", testName, p, err)
+		}
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	testName := "TestEntropy"
+	tests := []struct {
+		charset  string
+		length   int
+		expected float64
+	}{
+		{charset: "01", length: 8, expected: 8},
+		{charset: "0123456789abcdef", length: 32, expected: 128},
+		{charset: "aab", length: 4, expected: 4},
+		{charset: "", length: 10, expected: 0},
+	}
+	for _, test := range tests {
+		if actual := Entropy(test.charset, test.length); math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("%s failed (%q, %d): expected %f, got %f", testName, test.charset, test.length, test.expected, actual)
+		}
+	}
+
+	policy := PasswordPolicy{Length: 10, ExcludeAmbiguous: true}
+	expected := 10 * math.Log2(float64(len(policy.Charset())))
+	if actual := policy.Entropy(); math.Abs(actual-expected) > 1e-9 || len(policy.Charset()) != 82 {
+		t.Errorf("%s failed (policy): expected %f, got %f for charset %q", testName, expected, actual, policy.Charset())
+	}
+}
@@ -0,0 +1,167 @@
+// Package require provides the same assertions of the assert package, stopping the test at the first failure
+package require
+
+import (
+	"time"
+
+	"github.com/gyozatech/sushi/utils"
+	"github.com/gyozatech/sushi/utils/assert"
+)
+
+// TestingT is the subset of testing.TB used by the assertions
+type TestingT interface {
+	assert.TestingT
+	FailNow()
+}
+
+// Equal requires two comparable values to be equal
+func Equal[T comparable](t TestingT, expected, actual T, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Equal(t, expected, actual, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// NotEqual requires two comparable values not to be equal
+func NotEqual[T comparable](t TestingT, unexpected, actual T, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.NotEqual(t, unexpected, actual, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// DeepEqual requires two values to be structurally equal according to utils.DeepEqual
+func DeepEqual(t TestingT, expected, actual any, options ...utils.CompareOption) {
+	t.Helper()
+	if !assert.DeepEqual(t, expected, actual, options...) {
+		t.FailNow()
+	}
+}
+
+// JSONEq requires two JSON documents to be equivalent
+func JSONEq(t TestingT, expected, actual string, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.JSONEq(t, expected, actual, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// True requires a condition to be true
+func True(t TestingT, condition bool, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.True(t, condition, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// False requires a condition to be false
+func False(t TestingT, condition bool, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.False(t, condition, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Nil requires a value to be nil
+func Nil(t TestingT, value any, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Nil(t, value, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// NotNil requires a value not to be nil
+func NotNil(t TestingT, value any, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.NotNil(t, value, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// NoError requires an error to be nil
+func NoError(t TestingT, err error, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.NoError(t, err, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Error requires an error not to be nil
+func Error(t TestingT, err error, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Error(t, err, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// ErrorIs requires an error to match the target according to errors.Is
+func ErrorIs(t TestingT, err, target error, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.ErrorIs(t, err, target, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// ErrorContains requires an error message to contain the given text
+func ErrorContains(t TestingT, err error, text string, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.ErrorContains(t, err, text, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Contains requires a slice to contain an element
+func Contains[T comparable](t TestingT, slice []T, element T, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Contains(t, slice, element, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// NotContains requires a slice not to contain an element
+func NotContains[T comparable](t TestingT, slice []T, element T, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.NotContains(t, slice, element, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// ContainsString requires a string to contain a substring
+func ContainsString(t TestingT, s, substring string, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.ContainsString(t, s, substring, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Len requires a slice, map, string, array or channel to have the given length
+func Len(t TestingT, value any, length int, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Len(t, value, length, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Eventually requires a condition to become true within the timeout, checking it every tick
+func Eventually(t TestingT, condition func() bool, timeout, tick time.Duration, msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Eventually(t, condition, timeout, tick, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// Panics requires a function to panic
+func Panics(t TestingT, fn func(), msgAndArgs ...any) {
+	t.Helper()
+	if !assert.Panics(t, fn, msgAndArgs...) {
+		t.FailNow()
+	}
+}
+
+// NotPanics requires a function not to panic
+func NotPanics(t TestingT, fn func(), msgAndArgs ...any) {
+	t.Helper()
+	if !assert.NotPanics(t, fn, msgAndArgs...) {
+		t.FailNow()
+	}
+}
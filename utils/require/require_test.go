@@ -0,0 +1,44 @@
+package require
+
+import (
+	"io"
+	"testing"
+)
+
+// recorder is a TestingT recording the failures instead of reporting them
+type recorder struct {
+	errors  int
+	stopped bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors++
+}
+
+func (r *recorder) FailNow() {
+	r.stopped = true
+}
+
+func TestRequire(t *testing.T) {
+	testName := "TestRequire"
+
+	testCases := []struct {
+		description string
+		assertion   func(r *recorder)
+		expected    bool
+	}{
+		{description: "Equal", assertion: func(r *recorder) { Equal(r, 1, 1) }, expected: false},
+		{description: "Equal failing", assertion: func(r *recorder) { Equal(r, 1, 2) }, expected: true},
+		{description: "NoError failing", assertion: func(r *recorder) { NoError(r, io.EOF) }, expected: true},
+		{description: "DeepEqual", assertion: func(r *recorder) { DeepEqual(r, []int{1}, []int{1}) }, expected: false},
+	}
+	for _, testCase := range testCases {
+		r := &recorder{}
+		testCase.assertion(r)
+		if r.stopped != testCase.expected || (r.errors > 0) != testCase.expected {
+			t.Errorf("%s failed (%s): expected the test to be stopped: %t, got %t", testName, testCase.description, testCase.expected, r.stopped)
+		}
+	}
+}
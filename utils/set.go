@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Set is a hash-based collection of unique elements.
+// The zero value is a nil Set which can be read but not written: use NewSet to create one.
+type Set[T comparable] map[T]struct{}
+
+// NewSet creates a set with the given elements
+func NewSet[T comparable](elements ...T) Set[T] {
+	s := make(Set[T], len(elements))
+	s.Add(elements...)
+	return s
+}
+
+// Add adds the elements to the set
+func (s Set[T]) Add(elements ...T) {
+	for _, el := range elements {
+		s[el] = struct{}{}
+	}
+}
+
+// Remove removes the elements from the set
+func (s Set[T]) Remove(elements ...T) {
+	for _, el := range elements {
+		delete(s, el)
+	}
+}
+
+// Has returns true if the element belongs to the set
+func (s Set[T]) Has(element T) bool {
+	_, ok := s[element]
+	return ok
+}
+
+// Len returns the number of elements of the set
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Values returns the elements of the set in no particular order:
+// use SortedValues to get them in a deterministic order
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for el := range s {
+		values = append(values, el)
+	}
+	return values
+}
+
+// Clone returns a copy of the set
+func (s Set[T]) Clone() Set[T] {
+	clone := make(Set[T], len(s))
+	for el := range s {
+		clone[el] = struct{}{}
+	}
+	return clone
+}
+
+// Union returns a new set with the elements belonging to either of the sets
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	result := s.Clone()
+	for el := range other {
+		result[el] = struct{}{}
+	}
+	return result
+}
+
+// Intersection returns a new set with the elements belonging to both the sets
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	smaller, bigger := s, other
+	if len(smaller) > len(bigger) {
+		smaller, bigger = bigger, smaller
+	}
+	result := Set[T]{}
+	for el := range smaller {
+		if bigger.Has(el) {
+			result[el] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference returns a new set with the elements of s not belonging to other
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := Set[T]{}
+	for el := range s {
+		if !other.Has(el) {
+			result[el] = struct{}{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns a new set with the elements belonging to only one of the sets
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	result := s.Difference(other)
+	for el := range other {
+		if !s.Has(el) {
+			result[el] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubset returns true if all the elements of s belong to other
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for el := range s {
+		if !other.Has(el) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all the elements of other belong to s
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal returns true if the two sets contain the same elements
+func (s Set[T]) Equal(other Set[T]) bool {
+	return len(s) == len(other) && s.IsSubset(other)
+}
+
+// MarshalJSON encodes the set as a JSON array, sorted when the elements are numbers or strings
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	values := s.Values()
+	sortIfOrdered(values)
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON decodes a JSON array into the set, discarding the duplicates
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*s = NewSet(values...)
+	return nil
+}
+
+// SortedValues returns the elements of the set in ascending order
+func SortedValues[T Ordered](s Set[T]) []T {
+	values := s.Values()
+	sort.Slice(values, func(i, j int) bool {
+		return values[i] < values[j]
+	})
+	return values
+}
+
+// sortIfOrdered sorts in place a slice whose elements have an ordered underlying kind, leaving the others untouched
+func sortIfOrdered[T any](values []T) {
+	if len(values) < 2 {
+		return
+	}
+	rv := reflect.ValueOf(values)
+	var less func(a, b reflect.Value) bool
+	switch rv.Index(0).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.String:
+		less = func(a, b reflect.Value) bool { return a.String() < b.String() }
+	default:
+		return
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return less(rv.Index(i), rv.Index(j))
+	})
+}
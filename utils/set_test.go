@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSetOperations(t *testing.T) {
+	testName := "TestSetOperations"
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	testCases := []struct {
+		description string
+		actual      Set[int]
+		expected    []int
+	}{
+		{description: "Union", actual: a.Union(b), expected: []int{1, 2, 3, 4, 5}},
+		{description: "Intersection", actual: a.Intersection(b), expected: []int{3, 4}},
+		{description: "Difference", actual: a.Difference(b), expected: []int{1, 2}},
+		{description: "SymmetricDifference", actual: a.SymmetricDifference(b), expected: []int{1, 2, 5}},
+		{description: "Intersection with a nil set", actual: a.Intersection(nil), expected: []int{}},
+	}
+	for _, testCase := range testCases {
+		if actual := SortedValues(testCase.actual); !reflect.DeepEqual(testCase.expected, actual) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+
+	if a.Len() != 4 || !a.Has(1) || a.Has(5) {
+		t.Errorf("%s failed: the operations must not modify the operands, got %v", testName, SortedValues(a))
+	}
+	if !NewSet(3, 4).IsSubset(a) || a.IsSubset(b) || !a.IsSuperset(NewSet(1)) {
+		t.Errorf("%s failed: wrong subset relations", testName)
+	}
+	if !NewSet(1, 2).Equal(NewSet(2, 1, 1)) || NewSet(1, 2).Equal(NewSet(1, 3)) {
+		t.Errorf("%s failed: wrong equality", testName)
+	}
+
+	a.Remove(1, 2)
+	if !a.Equal(NewSet(3, 4)) {
+		t.Errorf("%s failed: expected [3 4] after removal, got %v", testName, SortedValues(a))
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	testName := "TestSetJSON"
+
+	encoded, err := json.Marshal(NewSet("pear", "apple", "fig"))
+	if err != nil || string(encoded) != `["apple","fig","pear"]` {
+		t.Errorf("%s failed: expected a sorted array, got %s (%v)", testName, encoded, err)
+	}
+
+	var decoded struct {
+		Tags Set[string] `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(`{"tags":["a","b","a"]}`), &decoded); err != nil {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+	if !decoded.Tags.Equal(NewSet("a", "b")) {
+		t.Errorf("%s failed: expected [a b], got %v", testName, decoded.Tags.Values())
+	}
+	if err := json.Unmarshal([]byte(`{"tags":"a"}`), &decoded); err == nil {
+		t.Errorf("%s failed: expected an error decoding a string into a set", testName)
+	}
+}
+
+func TestAppendUnique(t *testing.T) {
+	testName := "TestAppendUnique"
+	large := make([]int, 100)
+	for i := range large {
+		large[i] = i
+	}
+
+	testCases := []struct {
+		description string
+		slice       []int
+		elements    []int
+		expected    []int
+	}{
+		{description: "Small slice", slice: []int{1, 2}, elements: []int{2, 3, 3}, expected: []int{1, 2, 3}},
+		{description: "Nil slice", slice: nil, elements: []int{1, 1}, expected: []int{1}},
+		{description: "Large slice", slice: large, elements: []int{99, 100, 100, 0}, expected: append(append([]int{}, large...), 100)},
+	}
+	for _, testCase := range testCases {
+		if actual := AppendUnique(testCase.slice, testCase.elements...); !reflect.DeepEqual(testCase.expected, actual) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+}
+
+func TestRemoveElements(t *testing.T) {
+	testName := "TestRemoveElements"
+
+	testCases := []struct {
+		description string
+		list        []string
+		elements    []string
+		expected    []string
+	}{
+		{description: "One element", list: []string{"a", "b", "a"}, elements: []string{"a"}, expected: []string{"b"}},
+		{description: "Many elements", list: []string{"a", "b", "c", "a"}, elements: []string{"a", "c"}, expected: []string{"b"}},
+		{description: "No elements", list: []string{"a"}, elements: nil, expected: []string{"a"}},
+	}
+	for _, testCase := range testCases {
+		if actual := RemoveElements(testCase.list, testCase.elements...); !reflect.DeepEqual(testCase.expected, actual) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, actual)
+		}
+	}
+
+	// RemoveElement keeps its signature, e.g. to be used as a function value
+	var remove func([]string, string) []string = RemoveElement[string]
+	if expected, actual := []string{"b"}, remove([]string{"a", "b", "a"}, "a"); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("%s failed (RemoveElement): expected %v, got %v", testName, expected, actual)
+	}
+}
+
+func BenchmarkAppendUnique(b *testing.B) {
+	elements := make([]int, 10000)
+	for i := range elements {
+		elements[i] = i % 5000
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		AppendUnique([]int{}, elements...)
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "reflect"
+import (
+	"reflect"
+	"sort"
+)
 
 // Contains searches if an element is present in a slice
 func Contains[T comparable](slice []T, element T) bool {
@@ -23,10 +26,23 @@ func TruncateSlice[T any](slice []T, maxLength int) []T {
 	return slice
 }
 
+// linearScanThreshold is the size under which scanning a slice is cheaper than building a Set
+const linearScanThreshold = 16
+
 // AppendUnique appends elements to a slice with the guarantee of uniqueneness
 func AppendUnique[T comparable](slice []T, elements ...T) []T {
+	if len(slice)+len(elements) <= linearScanThreshold {
+		for _, el := range elements {
+			if !Contains(slice, el) {
+				slice = append(slice, el)
+			}
+		}
+		return slice
+	}
+	seen := NewSet(slice...)
 	for _, el := range elements {
-		if !Contains(slice, el) {
+		if !seen.Has(el) {
+			seen.Add(el)
 			slice = append(slice, el)
 		}
 	}
@@ -49,6 +65,21 @@ func RemoveElement[T comparable](list []T, element T) []T {
 	return newList
 }
 
+// RemoveElements removes the elements from the list, looking them up in a Set
+func RemoveElements[T comparable](list []T, elements ...T) []T {
+	if len(elements) == 1 {
+		return RemoveElement(list, elements[0])
+	}
+	newList := []T{}
+	removed := NewSet(elements...)
+	for _, e := range list {
+		if !removed.Has(e) {
+			newList = append(newList, e)
+		}
+	}
+	return newList
+}
+
 // RemoveEmptyValueInSlice Function to empty value in a slice
 func RemoveEmptyValueInSlice[T comparable](slice []T) []T {
 	var result []T
@@ -80,3 +111,226 @@ func FindMany[T any](slice []T, where func(t T) bool) []T {
 	}
 	return result
 }
+
+// Map applies a transformation to every element of a slice
+func Map[T any, V any](slice []T, fn func(t T) V) []V {
+	result := make([]V, len(slice))
+	for i, item := range slice {
+		result[i] = fn(item)
+	}
+	return result
+}
+
+// Filter returns the elements of a slice which verify a given condition
+func Filter[T any](slice []T, where func(t T) bool) []T {
+	return FindMany(slice, where)
+}
+
+// Reduce folds a slice into a single value, starting from an initial value
+func Reduce[T any, V any](slice []T, initial V, fn func(acc V, t T) V) V {
+	acc := initial
+	for _, item := range slice {
+		acc = fn(acc, item)
+	}
+	return acc
+}
+
+// GroupBy groups the elements of a slice by the key computed for each of them, preserving their order
+func GroupBy[T any, K comparable](slice []T, key func(t T) K) map[K][]T {
+	result := map[K][]T{}
+	for _, item := range slice {
+		k := key(item)
+		result[k] = append(result[k], item)
+	}
+	return result
+}
+
+// KeyBy maps every element of a slice to its key: if two elements share the same key, the last one wins
+func KeyBy[T any, K comparable](slice []T, key func(t T) K) map[K]T {
+	result := make(map[K]T, len(slice))
+	for _, item := range slice {
+		result[key(item)] = item
+	}
+	return result
+}
+
+// IndexBy maps the key of every element of a slice to its position: if two elements share the same key, the last one wins
+func IndexBy[T any, K comparable](slice []T, key func(t T) K) map[K]int {
+	result := make(map[K]int, len(slice))
+	for i, item := range slice {
+		result[key(item)] = i
+	}
+	return result
+}
+
+// Partition splits a slice into the elements which verify a given condition and the ones which don't
+func Partition[T any](slice []T, where func(t T) bool) (matching []T, others []T) {
+	matching, others = []T{}, []T{}
+	for _, item := range slice {
+		if where(item) {
+			matching = append(matching, item)
+		} else {
+			others = append(others, item)
+		}
+	}
+	return matching, others
+}
+
+// Chunk splits a slice into consecutive sub-slices of the given size, the last one possibly being shorter.
+// The chunks share the memory of the original slice but appending to one of them never overwrites the next one.
+func Chunk[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		panic("utils.Chunk: size must be positive")
+	}
+	result := make([][]T, 0, (len(slice)+size-1)/size)
+	for start := 0; start < len(slice); start += size {
+		end := start + size
+		if end > len(slice) {
+			end = len(slice)
+		}
+		result = append(result, slice[start:end:end])
+	}
+	return result
+}
+
+// Window returns all the sliding windows of the given size over a slice.
+// The windows share the memory of the original slice but appending to one of them never overwrites the original.
+func Window[T any](slice []T, size int) [][]T {
+	if size <= 0 {
+		panic("utils.Window: size must be positive")
+	}
+	if len(slice) < size {
+		return [][]T{}
+	}
+	result := make([][]T, 0, len(slice)-size+1)
+	for start := 0; start+size <= len(slice); start++ {
+		result = append(result, slice[start:start+size:start+size])
+	}
+	return result
+}
+
+// Pair holds two values of possibly different types
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs the elements of two slices by position, stopping at the end of the shortest one
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	length := len(a)
+	if len(b) < length {
+		length = len(b)
+	}
+	result := make([]Pair[A, B], length)
+	for i := 0; i < length; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Unzip splits a slice of pairs into two slices
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, pair := range pairs {
+		a[i], b[i] = pair.First, pair.Second
+	}
+	return a, b
+}
+
+// Flatten concatenates a slice of slices into a single slice
+func Flatten[T any](slices [][]T) []T {
+	length := 0
+	for _, s := range slices {
+		length += len(s)
+	}
+	result := make([]T, 0, length)
+	for _, s := range slices {
+		result = append(result, s...)
+	}
+	return result
+}
+
+// DistinctBy removes the elements whose key has already been found earlier in the slice
+func DistinctBy[T any, K comparable](slice []T, key func(t T) K) []T {
+	seen := Set[K]{}
+	result := []T{}
+	for _, item := range slice {
+		if k := key(item); !seen.Has(k) {
+			seen.Add(k)
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// MinBy returns the first element with the minimum key, or nil if the slice is empty
+func MinBy[T any, K Ordered](slice []T, key func(t T) K) *T {
+	return extremeBy(slice, key, func(a, b K) bool { return a < b })
+}
+
+// MaxBy returns the first element with the maximum key, or nil if the slice is empty
+func MaxBy[T any, K Ordered](slice []T, key func(t T) K) *T {
+	return extremeBy(slice, key, func(a, b K) bool { return a > b })
+}
+
+func extremeBy[T any, K Ordered](slice []T, key func(t T) K, better func(a, b K) bool) *T {
+	if len(slice) == 0 {
+		return nil
+	}
+	best, bestKey := 0, key(slice[0])
+	for i := 1; i < len(slice); i++ {
+		if k := key(slice[i]); better(k, bestKey) {
+			best, bestKey = i, k
+		}
+	}
+	result := slice[best]
+	return &result
+}
+
+// SumBy sums the values computed for every element of a slice
+func SumBy[T any, N Number](slice []T, value func(t T) N) N {
+	var sum N
+	for _, item := range slice {
+		sum += value(item)
+	}
+	return sum
+}
+
+// Comparator compares two values returning a negative number if a < b, zero if a == b, a positive number if a > b
+type Comparator[T any] func(a, b T) int
+
+// Ascending builds a Comparator ordering by the given key from the smallest to the greatest
+func Ascending[T any, K Ordered](key func(t T) K) Comparator[T] {
+	return func(a, b T) int {
+		ka, kb := key(a), key(b)
+		switch {
+		case ka < kb:
+			return -1
+		case ka > kb:
+			return 1
+		}
+		return 0
+	}
+}
+
+// Descending builds a Comparator ordering by the given key from the greatest to the smallest
+func Descending[T any, K Ordered](key func(t T) K) Comparator[T] {
+	ascending := Ascending(key)
+	return func(a, b T) int {
+		return ascending(b, a)
+	}
+}
+
+// SortBy sorts a slice in place by multiple keys: the elements equal for a comparator are ordered by the next one.
+// The sort is stable, so the elements equal for all the comparators keep their original order.
+func SortBy[T any](slice []T, comparators ...Comparator[T]) []T {
+	sort.SliceStable(slice, func(i, j int) bool {
+		for _, compare := range comparators {
+			if c := compare(slice[i], slice[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return slice
+}
@@ -0,0 +1,192 @@
+package utils
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type employee struct {
+	Name   string
+	Team   string
+	Salary int
+}
+
+var employees = []employee{
+	{Name: "Alice", Team: "backend", Salary: 50},
+	{Name: "Bob", Team: "frontend", Salary: 40},
+	{Name: "Carol", Team: "backend", Salary: 60},
+	{Name: "Dave", Team: "frontend", Salary: 40},
+}
+
+func names(slice []employee) []string {
+	return Map(slice, func(e employee) string { return e.Name })
+}
+
+func TestMapFilterReduce(t *testing.T) {
+	testName := "TestMapFilterReduce"
+
+	doubled := Map([]int{1, 2, 3}, func(i int) string { return strconv.Itoa(i * 2) })
+	if expected := []string{"2", "4", "6"}; !reflect.DeepEqual(expected, doubled) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, doubled)
+	}
+
+	backend := Filter(employees, func(e employee) bool { return e.Team == "backend" })
+	if expected := []string{"Alice", "Carol"}; !reflect.DeepEqual(expected, names(backend)) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, names(backend))
+	}
+
+	joined := Reduce(employees, "", func(acc string, e employee) string { return acc + e.Name[:1] })
+	if joined != "ABCD" {
+		t.Errorf("%s failed: expected ABCD, got %s", testName, joined)
+	}
+	if total := SumBy(employees, func(e employee) int { return e.Salary }); total != 190 {
+		t.Errorf("%s failed: expected 190, got %d", testName, total)
+	}
+}
+
+func TestGroupings(t *testing.T) {
+	testName := "TestGroupings"
+	byTeam := func(e employee) string { return e.Team }
+
+	groups := GroupBy(employees, byTeam)
+	if len(groups) != 2 || !reflect.DeepEqual([]string{"Bob", "Dave"}, names(groups["frontend"])) {
+		t.Errorf("%s failed: wrong groups %+v", testName, groups)
+	}
+
+	keyed := KeyBy(employees, byTeam)
+	if keyed["backend"].Name != "Carol" {
+		t.Errorf("%s failed: expected the last backend employee, got %+v", testName, keyed["backend"])
+	}
+	indexed := IndexBy(employees, func(e employee) string { return e.Name })
+	if indexed["Carol"] != 2 {
+		t.Errorf("%s failed: expected Carol at index 2, got %d", testName, indexed["Carol"])
+	}
+
+	matching, others := Partition(employees, func(e employee) bool { return e.Salary > 45 })
+	if !reflect.DeepEqual([]string{"Alice", "Carol"}, names(matching)) || !reflect.DeepEqual([]string{"Bob", "Dave"}, names(others)) {
+		t.Errorf("%s failed: wrong partition %v %v", testName, names(matching), names(others))
+	}
+
+	distinct := DistinctBy(employees, byTeam)
+	if !reflect.DeepEqual([]string{"Alice", "Bob"}, names(distinct)) {
+		t.Errorf("%s failed: expected the first employee of each team, got %v", testName, names(distinct))
+	}
+}
+
+func TestChunkAndWindow(t *testing.T) {
+	testName := "TestChunkAndWindow"
+	slice := []int{1, 2, 3, 4, 5}
+
+	chunks := Chunk(slice, 2)
+	if expected := [][]int{{1, 2}, {3, 4}, {5}}; !reflect.DeepEqual(expected, chunks) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, chunks)
+	}
+	_ = append(chunks[0], 100)
+	if slice[2] != 3 {
+		t.Errorf("%s failed: appending to a chunk must not overwrite the next one", testName)
+	}
+	if chunks := Chunk([]int{}, 3); len(chunks) != 0 {
+		t.Errorf("%s failed: expected no chunks, got %v", testName, chunks)
+	}
+
+	windows := Window(slice, 3)
+	if expected := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}; !reflect.DeepEqual(expected, windows) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, windows)
+	}
+	if windows := Window(slice, 6); len(windows) != 0 {
+		t.Errorf("%s failed: expected no windows, got %v", testName, windows)
+	}
+}
+
+func TestZipAndFlatten(t *testing.T) {
+	testName := "TestZipAndFlatten"
+
+	pairs := Zip([]string{"a", "b", "c"}, []int{1, 2})
+	if expected := []Pair[string, int]{{"a", 1}, {"b", 2}}; !reflect.DeepEqual(expected, pairs) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, pairs)
+	}
+	letters, numbers := Unzip(pairs)
+	if !reflect.DeepEqual([]string{"a", "b"}, letters) || !reflect.DeepEqual([]int{1, 2}, numbers) {
+		t.Errorf("%s failed: wrong unzip %v %v", testName, letters, numbers)
+	}
+
+	flat := Flatten([][]int{{1}, nil, {2, 3}})
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(expected, flat) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, flat)
+	}
+}
+
+func TestMinByMaxBy(t *testing.T) {
+	testName := "TestMinByMaxBy"
+	salary := func(e employee) int { return e.Salary }
+
+	if min := MinBy(employees, salary); min == nil || min.Name != "Bob" {
+		t.Errorf("%s failed: expected Bob, got %+v", testName, min)
+	}
+	if max := MaxBy(employees, salary); max == nil || max.Name != "Carol" {
+		t.Errorf("%s failed: expected Carol, got %+v", testName, max)
+	}
+	if min := MinBy([]employee{}, salary); min != nil {
+		t.Errorf("%s failed: expected nil for an empty slice, got %+v", testName, min)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	testName := "TestSortBy"
+	sorted := SortBy(append([]employee{}, employees...),
+		Descending(func(e employee) int { return e.Salary }),
+		Ascending(func(e employee) string { return strings.ToLower(e.Name) }),
+	)
+	if expected := []string{"Carol", "Alice", "Bob", "Dave"}; !reflect.DeepEqual(expected, names(sorted)) {
+		t.Errorf("%s failed: expected %v, got %v", testName, expected, names(sorted))
+	}
+
+	stable := SortBy(append([]employee{}, employees...), Ascending(func(e employee) string { return e.Team }))
+	if expected := []string{"Alice", "Carol", "Bob", "Dave"}; !reflect.DeepEqual(expected, names(stable)) {
+		t.Errorf("%s failed: expected a stable sort %v, got %v", testName, expected, names(stable))
+	}
+}
+
+func benchmarkSlice(size int) []employee {
+	slice := make([]employee, size)
+	for i := range slice {
+		slice[i] = employee{Name: strconv.Itoa(i), Team: strconv.Itoa(i % 10), Salary: i % 100}
+	}
+	return slice
+}
+
+func BenchmarkMap(b *testing.B) {
+	slice := benchmarkSlice(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Map(slice, func(e employee) int { return e.Salary })
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	slice := benchmarkSlice(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GroupBy(slice, func(e employee) string { return e.Team })
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	slice := benchmarkSlice(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Chunk(slice, 100)
+	}
+}
+
+func BenchmarkSortBy(b *testing.B) {
+	slice := benchmarkSlice(10000)
+	work := make([]employee, len(slice))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(work, slice)
+		SortBy(work, Descending(func(e employee) int { return e.Salary }), Ascending(func(e employee) string { return e.Name }))
+	}
+}
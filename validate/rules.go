@@ -0,0 +1,215 @@
+package validate
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gyozatech/sushi/utils"
+)
+
+// presenceRules are the rules receiving the field as it is, even if it's a nil pointer
+var presenceRules = map[string]bool{
+	"required":      true,
+	"required_with": true,
+}
+
+var builtinRules = map[string]Func{
+	"required": func(f Field) bool {
+		return !isEmpty(f.Value)
+	},
+	"required_with": func(f Field) bool {
+		other := f.Parent.FieldByName(f.Param)
+		return !other.IsValid() || isEmpty(other) || !isEmpty(f.Value)
+	},
+	"min": func(f Field) bool {
+		return compareParam(f, func(c int) bool { return c >= 0 })
+	},
+	"max": func(f Field) bool {
+		return compareParam(f, func(c int) bool { return c <= 0 })
+	},
+	"len": func(f Field) bool {
+		return compareParam(f, func(c int) bool { return c == 0 })
+	},
+	"oneof": func(f Field) bool {
+		value, ok := scalarString(f.Value)
+		return ok && utils.Contains(strings.Fields(f.Param), value)
+	},
+	"email": func(f Field) bool {
+		if f.Value.Kind() != reflect.String {
+			return false
+		}
+		address, err := mail.ParseAddress(f.Value.String())
+		return err == nil && address.Address == f.Value.String()
+	},
+	"url": func(f Field) bool {
+		if f.Value.Kind() != reflect.String {
+			return false
+		}
+		u, err := url.ParseRequestURI(f.Value.String())
+		return err == nil && u.Scheme != "" && u.Host != ""
+	},
+	"ipv4": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && isIPv4(f.Value.String())
+	},
+	"ipv6": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && isIPv6(f.Value.String())
+	},
+	"ip": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && (isIPv4(f.Value.String()) || isIPv6(f.Value.String()))
+	},
+	"cidr": func(f Field) bool {
+		if f.Value.Kind() != reflect.String {
+			return false
+		}
+		s := f.Value.String()
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return false
+		}
+		return utils.ValidateIPv4(s, true) || (strings.Contains(s, ":") && utils.ValidateIPv6(s, true))
+	},
+	"alpha": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && alphaRegex.MatchString(f.Value.String())
+	},
+	"alphanum": func(f Field) bool {
+		return f.Value.Kind() == reflect.String && alphanumRegex.MatchString(f.Value.String())
+	},
+	"numeric": func(f Field) bool {
+		if f.Value.Kind() != reflect.String {
+			return false
+		}
+		_, err := strconv.ParseFloat(f.Value.String(), 64)
+		return err == nil
+	},
+	"eqfield": func(f Field) bool {
+		return compareField(f, false, func(c int) bool { return c == 0 })
+	},
+	"nefield": func(f Field) bool {
+		return compareField(f, false, func(c int) bool { return c != 0 })
+	},
+	"gtfield": func(f Field) bool {
+		return compareField(f, true, func(c int) bool { return c > 0 })
+	},
+	"gtefield": func(f Field) bool {
+		return compareField(f, true, func(c int) bool { return c >= 0 })
+	},
+	"ltfield": func(f Field) bool {
+		return compareField(f, true, func(c int) bool { return c < 0 })
+	},
+	"ltefield": func(f Field) bool {
+		return compareField(f, true, func(c int) bool { return c <= 0 })
+	},
+}
+
+var (
+	alphaRegex    = regexp.MustCompile(`^[A-Za-z]+$`)
+	alphanumRegex = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+)
+
+func isIPv4(s string) bool {
+	return utils.ValidateIPv4(s, false) && net.ParseIP(s) != nil
+}
+
+func isIPv6(s string) bool {
+	return strings.Contains(s, ":") && utils.ValidateIPv6(s, false) && net.ParseIP(s) != nil
+}
+
+// compareParam compares the field with the numeric parameter of the rule:
+// numbers are compared by value, strings by number of characters, slices and maps by length
+func compareParam(f Field, check func(c int) bool) bool {
+	param, err := strconv.ParseFloat(f.Param, 64)
+	if err != nil {
+		return false
+	}
+	var actual float64
+	switch v := f.Value; v.Kind() {
+	case reflect.String:
+		actual = float64(utf8.RuneCountInString(v.String()))
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		actual = float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		actual = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = v.Float()
+	default:
+		return false
+	}
+	return check(compareFloats(actual, param))
+}
+
+// compareField compares the field with the sibling field named by the parameter of the rule:
+// when ordered is false the fields only need to be comparable for equality
+func compareField(f Field, ordered bool, check func(c int) bool) bool {
+	if !f.Parent.IsValid() {
+		return false
+	}
+	other := indirect(f.Parent.FieldByName(f.Param))
+	if !other.IsValid() {
+		return false
+	}
+	c, ok := compareValues(f.Value, other, ordered)
+	return ok && check(c)
+}
+
+// compareValues compares two values, false if they cannot be compared: the unexported fields are only compared
+// if they are strings or numbers, since their values cannot be read as interfaces
+func compareValues(a, b reflect.Value, ordered bool) (int, bool) {
+	readable := a.CanInterface() && b.CanInterface()
+	if readable && a.Type() == timeType && b.Type() == timeType {
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	}
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case a.CanInt() && b.CanInt():
+		return compareFloats(float64(a.Int()), float64(b.Int())), true
+	case a.CanUint() && b.CanUint():
+		return compareFloats(float64(a.Uint()), float64(b.Uint())), true
+	case a.CanFloat() && b.CanFloat():
+		return compareFloats(a.Float(), b.Float()), true
+	case !ordered && readable && a.Type() == b.Type() && a.Comparable() && b.Comparable():
+		// Comparable checks the dynamic values of the interfaces too, which Equal requires
+		if a.Equal(b) {
+			return 0, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func scalarString(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	}
+	return "", false
+}
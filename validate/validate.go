@@ -0,0 +1,286 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Field is the value being validated, as seen by a validation function
+type Field struct {
+	// Value is the value of the field, dereferenced if it's a pointer
+	Value reflect.Value
+	// Param is the parameter of the rule, e.g. "3" for min=3
+	Param string
+	// Parent is the struct containing the field, used by the cross-field rules
+	Parent reflect.Value
+}
+
+// Func is a validation function: it returns true if the field is valid
+type Func func(field Field) bool
+
+// FieldError describes a rule which is not satisfied by a field
+type FieldError struct {
+	// Path identifies the field, e.g. "Users[0].Email": fields are named after their json tag, if any
+	Path  string
+	Rule  string
+	Param string
+	Value any
+}
+
+func (e FieldError) Error() string {
+	if e.Param != "" {
+		return fmt.Sprintf("%s: failed rule %s=%s", e.Path, e.Rule, e.Param)
+	}
+	return fmt.Sprintf("%s: failed rule %s", e.Path, e.Rule)
+}
+
+// Errors is the list of all the rules not satisfied by a validated value
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Validator validates structs according to their `validate:"..."` tags.
+// A Validator is safe for concurrent use, but the custom rules should be registered before using it.
+type Validator struct {
+	rules  map[string]Func
+	fields sync.Map // map[reflect.Type][]fieldRules
+}
+
+// New creates a new Validator with the built-in rules
+func New() *Validator {
+	v := &Validator{rules: map[string]Func{}}
+	for name, fn := range builtinRules {
+		v.rules[name] = fn
+	}
+	return v
+}
+
+var defaultValidator = New()
+
+// Register adds a custom rule, or replaces an existing one, on the default Validator
+func Register(name string, fn Func) {
+	defaultValidator.Register(name, fn)
+}
+
+// Validate validates a struct (or a pointer, slice or map of structs) with the default Validator
+func Validate(value any) error {
+	return defaultValidator.Validate(value)
+}
+
+// Register adds a custom rule, or replaces an existing one
+func (v *Validator) Register(name string, fn Func) {
+	v.rules[name] = fn
+}
+
+// Validate validates a struct (or a pointer, slice or map of structs) returning Errors if any rule is not satisfied.
+// The rules are listed in the `validate` tag separated by commas, for example `validate:"required,min=3,max=20"`:
+// "omitempty" skips the other rules when the field is empty and "dive" applies the rules following it
+// to the elements of a slice or map. Nested structs are always validated.
+func (v *Validator) Validate(value any) error {
+	var errs Errors
+	if err := v.validateValue("", reflect.ValueOf(value), &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+type fieldRules struct {
+	index     int
+	name      string
+	omitEmpty bool
+	rules     []rule
+	dive      []rule
+	hasDive   bool
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (v *Validator) validateValue(path string, value reflect.Value, errs *Errors) error {
+	value = indirect(value)
+	if !value.IsValid() {
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		return v.validateStruct(path, value, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.validateValue(fmt.Sprintf("%s[%d]", path, i), value.Index(i), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := v.validateValue(fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value(), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) validateStruct(path string, value reflect.Value, errs *Errors) error {
+	fields, err := v.structRules(value.Type())
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		fieldPath := field.name
+		if path != "" {
+			fieldPath = path + "." + field.name
+		}
+		fieldValue := value.Field(field.index)
+		if field.omitEmpty && isEmpty(fieldValue) {
+			continue
+		}
+		v.applyRules(fieldPath, fieldValue, value, field.rules, errs)
+		if field.hasDive {
+			v.dive(fieldPath, fieldValue, value, field.dive, errs)
+		}
+		if err := v.validateValue(fieldPath, fieldValue, errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Validator) dive(path string, value, parent reflect.Value, rules []rule, errs *Errors) {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.applyRules(fmt.Sprintf("%s[%d]", path, i), value.Index(i), parent, rules, errs)
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			v.applyRules(fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value(), parent, rules, errs)
+		}
+	}
+}
+
+func (v *Validator) applyRules(path string, value, parent reflect.Value, rules []rule, errs *Errors) {
+	for _, r := range rules {
+		if r.name == "omitempty" {
+			if isEmpty(value) {
+				return
+			}
+			continue
+		}
+		fn := v.rules[r.name]
+		// a nil pointer only fails the rules about presence
+		if !presenceRules[r.name] && indirect(value).Kind() == reflect.Invalid {
+			continue
+		}
+		field := Field{Value: value, Param: r.param, Parent: parent}
+		if !presenceRules[r.name] {
+			field.Value = indirect(value)
+		}
+		if !fn(field) {
+			*errs = append(*errs, FieldError{Path: path, Rule: r.name, Param: r.param, Value: interfaceOf(value)})
+		}
+	}
+}
+
+// structRules returns the cached rules of the fields of a struct type
+func (v *Validator) structRules(t reflect.Type) ([]fieldRules, error) {
+	if cached, ok := v.fields.Load(t); ok {
+		return cached.([]fieldRules), nil
+	}
+	fields := []fieldRules{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fr := fieldRules{index: i, name: fieldName(field)}
+		tag := field.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+		for _, item := range strings.Split(tag, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			if item == "dive" {
+				fr.hasDive = true
+				continue
+			}
+			name, param, _ := strings.Cut(item, "=")
+			if _, ok := v.rules[name]; !ok && name != "omitempty" {
+				return nil, fmt.Errorf("validate: unknown rule %q on field %s.%s", name, t.Name(), field.Name)
+			}
+			r := rule{name: name, param: param}
+			switch {
+			case fr.hasDive:
+				fr.dive = append(fr.dive, r)
+			case name == "omitempty":
+				fr.omitEmpty = true
+			default:
+				fr.rules = append(fr.rules, r)
+			}
+		}
+		fields = append(fields, fr)
+	}
+	cached, _ := v.fields.LoadOrStore(t, fields)
+	return cached.([]fieldRules), nil
+}
+
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value if any of them is nil
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isEmpty(value reflect.Value) bool {
+	value = indirect(value)
+	if !value.IsValid() {
+		return true
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+func interfaceOf(value reflect.Value) any {
+	if value.IsValid() && value.CanInterface() {
+		return value.Interface()
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"len=5,numeric"`
+}
+
+type signup struct {
+	Username string            `json:"username" validate:"required,min=3,max=20,alphanum"`
+	Email    string            `json:"email" validate:"required,email"`
+	Password string            `json:"password" validate:"required,min=8"`
+	Confirm  string            `json:"confirm" validate:"eqfield=Password"`
+	Age      *int              `json:"age" validate:"omitempty,min=18"`
+	Plan     string            `json:"plan" validate:"oneof=free pro enterprise"`
+	Website  string            `json:"website" validate:"omitempty,url"`
+	Address  *address          `json:"address" validate:"required"`
+	Others   []address         `json:"others"`
+	Servers  []string          `json:"servers" validate:"max=3,dive,ip"`
+	Network  string            `json:"network" validate:"omitempty,cidr"`
+	Labels   map[string]string `json:"labels" validate:"dive,required"`
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end" validate:"gtfield=Start"`
+}
+
+func validSignup() signup {
+	age := 30
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return signup{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "s3cr3t-password",
+		Confirm:  "s3cr3t-password",
+		Age:      &age,
+		Plan:     "pro",
+		Address:  &address{City: "Rome", Zip: "00100"},
+		Servers:  []string{"10.0.0.1", "2001:db8::1"},
+		Network:  "10.0.0.0/8",
+		Labels:   map[string]string{"env": "prod"},
+		Start:    start,
+		End:      start.Add(time.Hour),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	testName := "TestValidate"
+	young := 16
+
+	testCases := []struct {
+		description string
+		mutate      func(s *signup)
+		expected    []FieldError
+	}{
+		{
+			description: "Valid struct",
+			mutate:      func(s *signup) {},
+			expected:    nil,
+		},
+		{
+			description: "Required and length rules",
+			mutate: func(s *signup) {
+				s.Username = "al"
+				s.Email = ""
+			},
+			expected: []FieldError{
+				{Path: "username", Rule: "min", Param: "3", Value: "al"},
+				{Path: "email", Rule: "required", Value: ""},
+				{Path: "email", Rule: "email", Value: ""},
+			},
+		},
+		{
+			description: "Pointers and omitempty",
+			mutate: func(s *signup) {
+				s.Age = &young
+				s.Address = nil
+			},
+			expected: []FieldError{
+				{Path: "age", Rule: "min", Param: "18", Value: &young},
+				{Path: "address", Rule: "required", Value: (*address)(nil)},
+			},
+		},
+		{
+			description: "Nested structs and slices",
+			mutate: func(s *signup) {
+				s.Address.Zip = "123"
+				s.Others = []address{{City: "Milan", Zip: "20100"}, {Zip: "20100"}}
+			},
+			expected: []FieldError{
+				{Path: "address.zip", Rule: "len", Param: "5", Value: "123"},
+				{Path: "others[1].city", Rule: "required", Value: ""},
+			},
+		},
+		{
+			description: "Dive into slices and maps",
+			mutate: func(s *signup) {
+				s.Servers = []string{"10.0.0.1", "999.0.0.1", "not-an-ip", "::1"}
+				s.Labels = map[string]string{"env": ""}
+			},
+			expected: []FieldError{
+				{Path: "servers", Rule: "max", Param: "3", Value: []string{"10.0.0.1", "999.0.0.1", "not-an-ip", "::1"}},
+				{Path: "servers[1]", Rule: "ip", Value: "999.0.0.1"},
+				{Path: "servers[2]", Rule: "ip", Value: "not-an-ip"},
+				{Path: "labels[env]", Rule: "required", Value: ""},
+			},
+		},
+		{
+			description: "Cross-field rules",
+			mutate: func(s *signup) {
+				s.Confirm = "different"
+				s.End = s.Start
+			},
+			expected: []FieldError{
+				{Path: "confirm", Rule: "eqfield", Param: "Password", Value: "different"},
+				{Path: "end", Rule: "gtfield", Param: "Start", Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			description: "Format rules",
+			mutate: func(s *signup) {
+				s.Plan = "gold"
+				s.Website = "example.com"
+				s.Network = "10.0.0.0"
+			},
+			expected: []FieldError{
+				{Path: "plan", Rule: "oneof", Param: "free pro enterprise", Value: "gold"},
+				{Path: "website", Rule: "url", Value: "example.com"},
+				{Path: "network", Rule: "cidr", Value: "10.0.0.0"},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		s := validSignup()
+		testCase.mutate(&s)
+		err := Validate(&s)
+		if testCase.expected == nil {
+			if err != nil {
+				t.Errorf("%s failed (%s): unexpected error %s", testName, testCase.description, err)
+			}
+			continue
+		}
+		var errs Errors
+		if !errors.As(err, &errs) || !reflect.DeepEqual(Errors(testCase.expected), errs) {
+			t.Errorf("%s failed (%s): expected %v, got %v", testName, testCase.description, testCase.expected, err)
+		}
+	}
+}
+
+func TestFieldComparisons(t *testing.T) {
+	testName := "TestFieldComparisons"
+	type unexported struct {
+		Flag  bool      `validate:"eqfield=flag"`
+		Start time.Time `validate:"gtfield=start"`
+		Name  string    `validate:"eqfield=name"`
+		flag  bool
+		start time.Time
+		name  string
+	}
+	type dynamic struct {
+		A any `validate:"eqfield=B"`
+		B any
+	}
+
+	testCases := []struct {
+		description string
+		value       any
+		failed      []string
+	}{
+		{description: "unexported fields", value: unexported{Name: "a", name: "a"}, failed: []string{"eqfield", "gtfield"}},
+		{description: "equal interfaces", value: dynamic{A: 1, B: 1}},
+		{description: "different interfaces", value: dynamic{A: 1, B: "1"}, failed: []string{"eqfield"}},
+		{description: "uncomparable interfaces", value: dynamic{A: []int{1}, B: []int{1}}, failed: []string{"eqfield"}},
+	}
+	for _, testCase := range testCases {
+		failed := []string{}
+		var errs Errors
+		if err := New().Validate(testCase.value); errors.As(err, &errs) {
+			for _, e := range errs {
+				failed = append(failed, e.Rule)
+			}
+		}
+		if len(failed) != len(testCase.failed) || (len(failed) > 0 && !reflect.DeepEqual(failed, testCase.failed)) {
+			t.Errorf("%s failed (%s): expected the failed rules %v, got %v", testName, testCase.description, testCase.failed, failed)
+		}
+	}
+}
+
+func TestCustomRules(t *testing.T) {
+	testName := "TestCustomRules"
+	type product struct {
+		SKU string `validate:"sku"`
+	}
+
+	v := New()
+	if err := v.Validate(product{SKU: "AB-1"}); err == nil {
+		t.Errorf("%s failed: expected an error for an unknown rule", testName)
+	}
+
+	v.Register("sku", func(f Field) bool {
+		return len(f.Value.String()) == 4 && f.Value.String()[2] == '-'
+	})
+	if err := v.Validate([]product{{SKU: "AB-1"}}); err != nil {
+		t.Errorf("%s failed: unexpected error %s", testName, err)
+	}
+	err := v.Validate([]product{{SKU: "AB-1"}, {SKU: "AB1"}})
+	if err == nil || err.Error() != "[1].SKU: failed rule sku" {
+		t.Errorf("%s failed: expected an error on the second product, got %v", testName, err)
+	}
+}